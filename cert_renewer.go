@@ -0,0 +1,151 @@
+package vault
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// certRenewer implements the background renewal shared by PKICertManager and SSHCertSigner.
+// The certificate is renewed after renewFraction of its lifetime, failed renewals are retried
+// with exponential backoff.
+type certRenewer struct {
+	// fetch obtains and stores a new certificate, it has to call setValidity on success
+	fetch func() error
+	// rotated is called after each successful renewal
+	rotated func()
+
+	renewFraction    float64
+	minRetryInterval time.Duration
+	maxRetryInterval time.Duration
+	onError          func(err error)
+	now              func() time.Time
+
+	validityMu sync.RWMutex
+	notBefore  time.Time
+	// notAfter is zero for certificates which never expire
+	notAfter time.Time
+}
+
+func (r *certRenewer) init(fetch func() error, rotated func()) {
+	r.fetch = fetch
+	r.rotated = rotated
+	r.renewFraction = 2.0 / 3.0
+	r.minRetryInterval = 5 * time.Second
+	r.maxRetryInterval = 5 * time.Minute
+	r.now = time.Now
+}
+
+func (r *certRenewer) setRenewFraction(fraction float64) error {
+	if fraction <= 0 || fraction >= 1 {
+		return errors.New("renew fraction has to be between 0 and 1")
+	}
+
+	r.renewFraction = fraction
+
+	return nil
+}
+
+func (r *certRenewer) setRetryInterval(minInterval time.Duration, maxInterval time.Duration) error {
+	if minInterval <= 0 || maxInterval < minInterval {
+		return errors.New("invalid retry interval")
+	}
+
+	r.minRetryInterval = minInterval
+	r.maxRetryInterval = maxInterval
+
+	return nil
+}
+
+// run renews the certificate until ctx is canceled
+func (r *certRenewer) run(ctx context.Context) error {
+	attempt := 0
+
+	for {
+		wait := r.renewIn()
+		if attempt > 0 {
+			wait = r.retryIn(attempt)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := r.renew(); err != nil {
+			attempt++
+			continue
+		}
+
+		attempt = 0
+	}
+}
+
+func (r *certRenewer) renew() error {
+	if err := r.fetch(); err != nil {
+		if r.onError != nil {
+			r.onError(err)
+		}
+
+		return err
+	}
+
+	if r.rotated != nil {
+		r.rotated()
+	}
+
+	return nil
+}
+
+func (r *certRenewer) setValidity(notBefore time.Time, notAfter time.Time) {
+	r.validityMu.Lock()
+	defer r.validityMu.Unlock()
+
+	r.notBefore = notBefore
+	r.notAfter = notAfter
+}
+
+// renewIn returns the duration until the current certificate has to be renewed
+func (r *certRenewer) renewIn() time.Duration {
+	r.validityMu.RLock()
+	notBefore, notAfter := r.notBefore, r.notAfter
+	r.validityMu.RUnlock()
+
+	if notAfter.IsZero() {
+		return time.Duration(1<<63 - 1)
+	}
+
+	renewAt := notBefore.Add(time.Duration(float64(notAfter.Sub(notBefore)) * r.renewFraction))
+
+	if wait := renewAt.Sub(r.now()); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+// retryIn returns the backoff for the given failed attempt
+func (r *certRenewer) retryIn(attempt int) time.Duration {
+	return retryBackoff(attempt, r.minRetryInterval, r.maxRetryInterval)
+}
+
+// retryBackoff returns the exponential backoff with up to 20% jitter for the given failed attempt
+func retryBackoff(attempt int, minInterval time.Duration, maxInterval time.Duration) time.Duration {
+	wait := maxInterval
+	if attempt < 32 {
+		if backoff := minInterval << (attempt - 1); backoff > 0 && backoff < wait {
+			wait = backoff
+		}
+	}
+
+	//nolint:gosec // jitter doesn't need a secure random source
+	jitter := time.Duration(rand.Int63n(int64(wait)/5 + 1))
+
+	return wait - jitter
+}
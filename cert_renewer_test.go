@@ -0,0 +1,42 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertRenewerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &certRenewer{}
+	fetches, errs := 0, 0
+
+	r.init(func() error {
+		fetches++
+		if fetches <= 2 {
+			return errors.New("renewal failed")
+		}
+
+		// the renewed certificate doesn't have to be renewed during the test
+		r.setValidity(time.Now(), time.Now().Add(time.Hour))
+
+		return nil
+	}, cancel)
+	r.onError = func(err error) { errs++ }
+	require.NoError(t, r.setRetryInterval(time.Millisecond, 5*time.Millisecond))
+
+	// the initial certificate is expired, failed renewals are retried until one succeeds
+	r.setValidity(time.Now().Add(-time.Hour), time.Now())
+	require.ErrorIs(t, r.run(ctx), context.Canceled)
+
+	require.Equal(t, 3, fetches)
+	require.Equal(t, 2, errs)
+
+	require.Error(t, r.setRenewFraction(1))
+	require.Error(t, r.setRetryInterval(time.Second, time.Millisecond))
+}
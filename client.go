@@ -1,23 +1,43 @@
 package vault
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/hashicorp/vault/api"
 )
 
+// namespaceHeaderName is the header selecting the namespace of a request
+const namespaceHeaderName = "X-Vault-Namespace"
+
 type Client struct {
 	*api.Client
 
 	auth    AuthProvider
 	conf    *api.Config
 	tlsConf *TLSConfig
+
+	// namespace overrides the namespace of the underlying client, see InNamespace
+	namespace string
+
+	// ctx cancels requests and waits between retries, see WithContext
+	ctx context.Context
+
+	retryPolicy RetryPolicy
+	retryHooks  []func(attempt RetryAttempt, wait time.Duration)
+
+	limiters []*requestLimiter
+
+	// failover is set if multiple addresses were passed to NewClient or WithCircuitBreaker was used
+	failover *failover
+	breaker  *CircuitBreaker
 }
 
 type Service struct {
@@ -36,6 +56,30 @@ type RequestOptions struct {
 	// This should generally only be disabled for TokenAuth requests (a failed TokenAuth request can't be fixed by
 	// doing another TokenAuth request, this would lead to infinite recursion)
 	SkipRenewal bool
+
+	// WrapTTL requests vault to wrap the response in a single-use token valid for the given duration (e.g. "5m"),
+	// the response then only contains WrapInfo, see WrappedResponse
+	WrapTTL string
+
+	// Token overrides the client token for this request, e.g. to authenticate using a wrapping token
+	Token string
+
+	// Namespace overrides the namespace of the client for this request
+	Namespace string
+
+	// Headers are added to the request
+	Headers http.Header
+
+	// Idempotent marks a write request as safe to be retried by the RetryPolicy on any retryable error,
+	// GET, LIST and DELETE requests are always considered idempotent
+	Idempotent bool
+}
+
+// RawBody is sent as request body as is, other bodies passed to Client.Request are encoded as JSON.
+// The response body is returned as is if a *[]byte is passed as response.
+type RawBody struct {
+	ContentType string
+	Data        []byte
 }
 
 type TLSConfig struct {
@@ -54,11 +98,19 @@ func WithCaPath(path string) *TLSConfig {
 	}
 }
 
+// NewClient creates a client for the vault server at addr. addr may contain multiple comma separated addresses
+// (e.g. "https://vault-1:8200,https://vault-2:8200"), requests are then sent to the first available active node
+// and fail over to the other addresses, see WithCircuitBreaker.
 func NewClient(addr string, tlsConf *TLSConfig, opts ...ClientOpts) (*Client, error) {
 	conf := api.DefaultConfig()
 
 	conf.Address = addr
 
+	addrs := splitAddresses(addr)
+	if len(addrs) > 1 {
+		conf.Address = addrs[0]
+	}
+
 	if tlsConf != nil {
 		if err := conf.ConfigureTLS(tlsConf.TLSConfig); err != nil {
 			return nil, err
@@ -83,6 +135,16 @@ func NewClient(addr string, tlsConf *TLSConfig, opts ...ClientOpts) (*Client, er
 		}
 	}
 
+	if len(addrs) > 1 || client.breaker != nil {
+		client.failover, err = newFailover(addrs, client.breaker)
+		if err != nil {
+			return nil, err
+		}
+
+		// the retries of the api client would repeat non-idempotent writes and delay the failover
+		client.SetMaxRetries(0)
+	}
+
 	if client.auth != nil {
 		if err := client.renewToken(); err != nil {
 			return nil, err
@@ -92,6 +154,70 @@ func NewClient(addr string, tlsConf *TLSConfig, opts ...ClientOpts) (*Client, er
 	return client, nil
 }
 
+// InNamespace returns a client sending all requests to the given namespace (e.g. "tenant-a" or "tenant-a/team-b"),
+// services created from it use this namespace. The returned client shares token and connection with c,
+// so one process can talk to several namespaces without authenticating multiple times.
+func (c *Client) InNamespace(namespace string) *Client {
+	namespaced := *c
+	namespaced.namespace = namespace
+
+	return &namespaced
+}
+
+// WithContext returns a client whose requests are canceled with ctx, including the waits between retries,
+// services created from it use ctx. Like InNamespace the returned client shares token and connection with c.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	withCtx := *c
+	withCtx.ctx = ctx
+
+	return &withCtx
+}
+
+// context returns the context of the requests
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+
+	return context.Background()
+}
+
+// Namespace returns the namespace requests are sent to
+func (c *Client) Namespace() string {
+	if c.namespace != "" {
+		return c.namespace
+	}
+
+	return c.Client.Namespace()
+}
+
+// rawClient returns the client sending the request, the api client always sets its own namespace header,
+// so namespace overrides have to be applied by a request callback
+func (c *Client) rawClient(opts *RequestOptions) *api.Client {
+	namespace := c.namespace
+	if opts.Namespace != "" {
+		namespace = opts.Namespace
+	}
+
+	if namespace == "" {
+		return c.Client
+	}
+
+	return c.Client.WithRequestCallbacks(func(r *api.Request) {
+		r.Headers.Set(namespaceHeaderName, namespace)
+	})
+}
+
+// send sends r, to the preferred address if multiple addresses were passed to NewClient
+func (c *Client) send(r *api.Request, path string, opts *RequestOptions) (*api.Response, error) {
+	if c.failover == nil {
+		//nolint:staticcheck
+		return c.rawClient(opts).RawRequestWithContext(c.context(), r)
+	}
+
+	return c.failover.send(c.context(), c.rawClient(opts), c.Client, r, path, opts.Idempotent || isIdempotent(r.Method))
+}
+
 func (c *Client) renewToken() error {
 	res, err := c.auth.Auth()
 	if err != nil {
@@ -112,12 +238,54 @@ func (c *Client) Request(method string, path []string, body, response interface{
 		opts = &RequestOptions{}
 	}
 
+	for attempt := 1; ; attempt++ {
+		err := c.request(method, path, body, response, opts)
+		if err == nil || c.retryPolicy == nil {
+			return err
+		}
+
+		failed := RetryAttempt{
+			Attempt:    attempt,
+			Method:     method,
+			Path:       resolvePath(path),
+			Idempotent: opts.Idempotent || isIdempotent(method),
+			Err:        err,
+		}
+
+		wait, retry := c.retryPolicy.NextRetry(failed)
+		if !retry {
+			return err
+		}
+
+		for _, hook := range c.retryHooks {
+			hook(failed, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.context().Done():
+			timer.Stop()
+			return newError(method, failed.Path, c.Namespace(),
+				errors.Wrapf(c.context().Err(), "canceled while waiting to retry after %q", err.Error()))
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) request(method string, path []string, body, response interface{}, opts *RequestOptions) error {
 	pathString := resolvePath(path)
 	r := c.NewRequest(method, pathString)
 
-	if body != nil {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = c.Namespace()
+	}
+
+	if raw, ok := body.(*RawBody); ok {
+		r.BodyBytes = raw.Data
+	} else if body != nil {
 		if err := r.SetJSONBody(body); err != nil {
-			return errors.Wrap(err, "failed to marshal body as JSON")
+			return newError(method, pathString, namespace, errors.Wrap(err, "failed to marshal body as JSON"))
 		}
 	}
 
@@ -125,10 +293,36 @@ func (c *Client) Request(method string, path []string, body, response interface{
 		r.Params = opts.Parameters
 	}
 
-	//nolint:staticcheck
-	resp, err := c.RawRequest(r)
+	if opts.WrapTTL != "" {
+		r.WrapTTL = opts.WrapTTL
+	}
+
+	if opts.Token != "" {
+		r.ClientToken = opts.Token
+	}
+
+	if r.Headers == nil {
+		r.Headers = http.Header{}
+	}
+
+	for name, values := range opts.Headers {
+		r.Headers[name] = values
+	}
+
+	if raw, ok := body.(*RawBody); ok && raw.ContentType != "" {
+		r.Headers.Set("Content-Type", raw.ContentType)
+	}
+
+	release, err := c.acquireLimits(pathString)
+	if err != nil {
+		return newError(method, pathString, namespace, err)
+	}
+
+	resp, err := c.send(r, pathString, opts)
+	release()
 	isTokenExpiredErr := resp != nil && resp.StatusCode == http.StatusForbidden && c.auth != nil
 	isCertExpiredErr := err != nil && errors.As(err, &x509.UnknownAuthorityError{})
+
 	if (isTokenExpiredErr || isCertExpiredErr) && !opts.SkipRenewal {
 		if resp != nil {
 			_ = resp.Body.Close()
@@ -137,23 +331,27 @@ func (c *Client) Request(method string, path []string, body, response interface{
 		if c.tlsConf != nil {
 			reloadErr := c.reloadTLSConfig()
 			if reloadErr != nil {
-				return errors.Wrapf(reloadErr, "tlsconfig reload failed after request failed with %q", err.Error())
+				return newError(method, pathString, namespace,
+					errors.Wrapf(reloadErr, "tlsconfig reload failed after request failed with %q", err.Error()))
 			}
 		}
 
 		if c.auth != nil {
 			tokenErr := c.renewToken()
 			if tokenErr != nil {
-				return errors.Wrap(tokenErr, "token renew after request returned 403 failed")
+				return newError(method, pathString, namespace,
+					errors.Wrap(tokenErr, "token renew after request returned 403 failed"))
 			}
 		}
 
 		// We have to build a new request, the new token has to be set in that one
-		// Renewal has to be skipped to make sure we never renew in a loop.
-		opts.SkipRenewal = true
-		return c.Request(method, path, body, response, opts)
+		// Renewal has to be skipped to make sure we never renew in a loop. The options are copied, so
+		// retries of the caller can renew again.
+		retryOpts := *opts
+		retryOpts.SkipRenewal = true
+		return c.request(method, path, body, response, &retryOpts)
 	} else if err != nil {
-		return errors.Wrap(err, "request failed")
+		return newError(method, pathString, namespace, err)
 	}
 	defer resp.Body.Close()
 
@@ -163,11 +361,21 @@ func (c *Client) Request(method string, path []string, body, response interface{
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "error reading response body")
+		return newError(method, pathString, namespace, errors.Wrap(err, "error reading response body"))
+	}
+
+	if raw, ok := response.(*[]byte); ok {
+		*raw = respBody
+		return nil
+	}
+
+	// some endpoints respond with 204 No Content, depending on the vault version
+	if len(respBody) == 0 {
+		return nil
 	}
 
 	if err = json.Unmarshal(respBody, response); err != nil {
-		return errors.Wrap(err, "error unmarshalling body into response struct")
+		return newError(method, pathString, namespace, errors.Wrap(err, "error unmarshalling body into response struct"))
 	}
 
 	return nil
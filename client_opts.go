@@ -1,5 +1,7 @@
 package vault
 
+import "time"
+
 type ClientOpts func(c *Client) error
 
 func WithKubernetesAuth(role string, opts ...KubernetesAuthOpt) ClientOpts {
@@ -34,3 +36,63 @@ func WithUserpassAuth(username string, password string, opts ...UserpassAuthOpt)
 		return nil
 	}
 }
+
+// WithNamespace sends all requests, including authentication, to the given namespace.
+// Use Client.InNamespace to talk to other namespaces using the same client.
+func WithNamespace(namespace string) ClientOpts {
+	return func(c *Client) error {
+		c.SetNamespace(namespace)
+		return nil
+	}
+}
+
+// WithRetryPolicy retries failed requests according to policy, e.g. DefaultRetryPolicy().
+// The retries of the underlying api client are disabled, as they don't consider idempotency.
+func WithRetryPolicy(policy RetryPolicy) ClientOpts {
+	return func(c *Client) error {
+		c.retryPolicy = policy
+		c.SetMaxRetries(0)
+
+		return nil
+	}
+}
+
+// WithRetryHook registers a function called before each retry, e.g. for logging or metrics
+func WithRetryHook(hook func(attempt RetryAttempt, wait time.Duration)) ClientOpts {
+	return func(c *Client) error {
+		c.retryHooks = append(c.retryHooks, hook)
+
+		return nil
+	}
+}
+
+// WithRateLimit applies client side rate and concurrency limits to all requests, e.g. to stay below the
+// rate limit quotas of vault. A request has to pass all limits whose PathPrefix matches its path, so a
+// global limit can be combined with limits for single mounts.
+func WithRateLimit(limits ...RateLimit) ClientOpts {
+	return func(c *Client) error {
+		for _, limit := range limits {
+			l, err := newRequestLimiter(limit)
+			if err != nil {
+				return err
+			}
+
+			c.limiters = append(c.limiters, l)
+		}
+
+		return nil
+	}
+}
+
+// WithCircuitBreaker configures the failover between the comma separated addresses passed to NewClient.
+// Requests are sent to the first active node in the order of the addresses, reads fall back to performance
+// standbys if no active node is available. No requests are sent to an address for OpenDuration after
+// FailureThreshold consecutive failures, afterwards it's used again once its health check succeeds.
+// Zero values are replaced by the defaults of DefaultCircuitBreaker.
+// The internal retries of the api client are disabled in failover mode, use WithRetryPolicy to retry requests.
+func WithCircuitBreaker(breaker CircuitBreaker) ClientOpts {
+	return func(c *Client) error {
+		c.breaker = &breaker
+		return nil
+	}
+}
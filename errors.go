@@ -1,8 +1,168 @@
 package vault
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
 
 var (
-	ErrEncKeyNotFound = errors.New("encryption key not found")
-	ErrIssuerNotFound = errors.New("issuer not found")
+	// ErrNotFound is matched by errors.Is for responses with status 404 and by not found errors of the services
+	ErrNotFound = errors.New("not found")
+	// ErrPermissionDenied is matched by errors.Is for responses with status 403
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrSealed is matched by errors.Is if the vault server is sealed
+	ErrSealed = errors.New("vault is sealed")
+	// ErrRateLimited is matched by errors.Is for responses with status 429, e.g. if a rate limit quota is exceeded
+	ErrRateLimited = errors.New("rate limited")
+	// ErrStandby is matched by errors.Is if the request was sent to a standby node which couldn't handle it
+	ErrStandby = errors.New("vault node is in standby mode")
+	// ErrUnavailable is returned if the circuit breakers of all addresses passed to NewClient are open
+	ErrUnavailable = errors.New("no vault address available")
+
+	ErrEncKeyNotFound     error = &kindError{msg: "encryption key not found", kind: ErrNotFound}
+	ErrIssuerNotFound     error = &kindError{msg: "issuer not found", kind: ErrNotFound}
+	ErrPKIKeyNotFound     error = &kindError{msg: "pki key not found", kind: ErrNotFound}
+	ErrSSHCANotConfigured error = &kindError{msg: "ssh CA not configured", kind: ErrNotFound}
 )
+
+// kindError is a sentinel error of a service, which also matches one of the generic sentinel errors
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Error is returned by Client.Request and all services if a request fails.
+// Use errors.As to access it, or errors.Is with the sentinel errors (e.g. ErrNotFound) to classify it.
+type Error struct {
+	// StatusCode is the HTTP status code of the response, it is 0 if no response was received
+	StatusCode int
+	// Errors are the error messages returned by vault
+	Errors []string
+	// Operation is the HTTP method of the request, e.g. "GET" or "LIST"
+	Operation string
+	// Path is the request path, e.g. "/v1/transit/keys/my-key"
+	Path string
+	// Namespace is the namespace of the request, if any
+	Namespace string
+
+	// Err is the underlying error, an *api.ResponseError if a response was received
+	Err error
+}
+
+func newError(operation string, path string, namespace string, err error) *Error {
+	e := &Error{
+		Operation: operation,
+		Path:      path,
+		Namespace: namespace,
+		Err:       err,
+	}
+
+	resErr := &api.ResponseError{}
+	if errors.As(err, &resErr) {
+		e.StatusCode = resErr.StatusCode
+		e.Errors = resErr.Errors
+	}
+
+	return e
+}
+
+func (e *Error) Error() string {
+	// errors which don't stem directly from the response, e.g. failed token renewals, are printed with their cause
+	if _, isResponseErr := e.Err.(*api.ResponseError); e.StatusCode == 0 || !isResponseErr {
+		return fmt.Sprintf("request %s %s failed: %v", e.Operation, e.Path, e.Err)
+	}
+
+	msg := fmt.Sprintf("request %s %s failed with status %d", e.Operation, e.Path, e.StatusCode)
+	if len(e.Errors) > 0 {
+		msg += ": " + strings.Join(e.Errors, "; ")
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// withErr returns a copy of e caused by err, services use it to return their sentinel errors
+// without losing status code, path and namespace of the request
+func (e *Error) withErr(err error) *Error {
+	c := *e
+	c.Err = err
+
+	return &c
+}
+
+// Is classifies the error by its status code and vault error messages
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrPermissionDenied:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrSealed:
+		return e.StatusCode == http.StatusServiceUnavailable && e.hasMessage("sealed")
+	case ErrStandby:
+		return e.StatusCode >= http.StatusInternalServerError && (e.hasMessage("standby") || e.hasMessage("node not active"))
+	}
+
+	return false
+}
+
+// hasMessage reports whether one of the vault error messages contains s, ignoring case
+func (e *Error) hasMessage(s string) bool {
+	for _, msg := range e.Errors {
+		if strings.Contains(strings.ToLower(msg), s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
+
+func IsSealed(err error) bool {
+	return errors.Is(err, ErrSealed)
+}
+
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+func IsStandby(err error) bool {
+	return errors.Is(err, ErrStandby)
+}
+
+// orNil implements the ...OrNil variants of read methods, errors matching ErrNotFound result in nil
+func orNil[T any](response *T, err error) (*T, error) {
+	if IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,285 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+	client *Client
+	server *httptest.Server
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
+
+// SetupTest starts a server responding to /v1/<status> with the given status code and the error message
+// passed in the msg parameter
+func (s *ErrorsTestSuite) SetupTest() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status int
+		_, err := fmt.Sscanf(r.URL.Path, "/v1/%d", &status)
+		require.NoError(s.T(), err)
+
+		errs := []string{}
+		if msg := r.URL.Query().Get("msg"); msg != "" {
+			errs = append(errs, msg)
+		}
+
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+	}))
+
+	var err error
+	s.client, err = NewClient(s.server.URL, nil, WithAuthToken("token"), WithNamespace("tenant"))
+	require.NoError(s.T(), err)
+
+	// the api client retries 5xx and 429 responses otherwise
+	s.client.SetMaxRetries(0)
+}
+
+func (s *ErrorsTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ErrorsTestSuite) request(status string, msg string) error {
+	return s.client.Read([]string{"v1", status}, &struct{}{}, &RequestOptions{
+		Parameters: map[string][]string{"msg": {msg}},
+	})
+}
+
+func (s *ErrorsTestSuite) TestError() {
+	err := s.request("404", "")
+
+	vaultErr := &Error{}
+	require.True(s.T(), errors.As(err, &vaultErr))
+	s.Equal(http.StatusNotFound, vaultErr.StatusCode)
+	s.Equal("GET", vaultErr.Operation)
+	s.Equal("/v1/404", vaultErr.Path)
+	s.Equal("tenant", vaultErr.Namespace)
+	s.Equal("request GET /v1/404 failed with status 404", err.Error())
+
+	// the response error of the api package is still accessible
+	resErr := &api.ResponseError{}
+	require.True(s.T(), errors.As(err, &resErr))
+	s.Equal(http.StatusNotFound, resErr.StatusCode)
+
+	err = s.request("400", "invalid request")
+	s.Equal("request GET /v1/400 failed with status 400: invalid request", err.Error())
+}
+
+func (s *ErrorsTestSuite) TestClassification() {
+	sentinels := []error{ErrNotFound, ErrPermissionDenied, ErrRateLimited, ErrSealed, ErrStandby}
+
+	for _, tc := range []struct {
+		status string
+		msg    string
+		kind   error
+	}{
+		{status: "404", kind: ErrNotFound},
+		{status: "403", msg: "permission denied", kind: ErrPermissionDenied},
+		{status: "429", msg: "request path \"kv/\": rate limit quota exceeded", kind: ErrRateLimited},
+		{status: "503", msg: "Vault is sealed", kind: ErrSealed},
+		{status: "500", msg: "local node not active but active cluster node not found", kind: ErrStandby},
+		{status: "503", msg: "Vault is in standby mode", kind: ErrStandby},
+	} {
+		err := s.request(tc.status, tc.msg)
+
+		for _, sentinel := range sentinels {
+			s.Equal(sentinel == tc.kind, errors.Is(err, sentinel), "%s %s is %v", tc.status, tc.msg, sentinel)
+		}
+	}
+
+	s.True(IsNotFound(s.request("404", "")))
+	s.True(IsPermissionDenied(s.request("403", "")))
+	s.True(IsRateLimited(s.request("429", "")))
+	s.True(IsSealed(s.request("503", "Vault is sealed")))
+	s.True(IsStandby(s.request("503", "Vault is in standby mode")))
+
+	s.False(IsNotFound(s.request("500", "internal error")))
+	s.False(IsSealed(s.request("503", "unavailable")))
+}
+
+func (s *ErrorsTestSuite) TestServiceErrors() {
+	s.True(IsNotFound(ErrEncKeyNotFound))
+	s.True(IsNotFound(ErrIssuerNotFound))
+	s.False(IsPermissionDenied(ErrEncKeyNotFound))
+
+	s.server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"encryption key not found"}})
+	})
+
+	_, err := s.client.Transit().Decrypt("missing", &TransitDecryptOptions{Ciphertext: "vault:v1:abc"})
+	s.True(errors.Is(err, ErrEncKeyNotFound))
+	s.True(errors.Is(err, ErrNotFound))
+
+	// the request details are kept
+	vaultErr := &Error{}
+	s.Require().True(errors.As(err, &vaultErr))
+	s.Equal(http.StatusBadRequest, vaultErr.StatusCode)
+	s.Equal("/v1/transit/decrypt/missing", vaultErr.Path)
+	s.Equal("tenant", vaultErr.Namespace)
+}
+
+func TestTransportError(t *testing.T) {
+	client, err := NewClient("http://127.0.0.1:1", nil, WithAuthToken("token"))
+	require.NoError(t, err)
+	client.SetMaxRetries(0)
+
+	err = client.Read([]string{"v1", "sys", "health"}, &struct{}{}, nil)
+
+	vaultErr := &Error{}
+	require.True(t, errors.As(err, &vaultErr))
+	require.Zero(t, vaultErr.StatusCode)
+	require.False(t, IsNotFound(err))
+}
+
+func TestNotFoundAcrossServices(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"/v1/kv/missing":                 {http.StatusNotFound, `{"errors":[]}`},
+		"/v1/transit/keys/missing":       {http.StatusNotFound, `{"errors":[]}`},
+		"/v1/pki/cert/01:02":             {http.StatusOK, `{"data":{"certificate":""}}`},
+		"/v1/pki/issuer/missing/json":    {http.StatusInternalServerError, `{"errors":["unable to find PKI issuer for reference: missing"]}`},
+		"/v1/pki/key/missing":            {http.StatusBadRequest, `{"errors":["unable to find PKI key for reference: missing"]}`},
+		"/v1/pki/roles/missing":          {http.StatusNotFound, `{"errors":[]}`},
+		"/v1/ssh/roles/missing":          {http.StatusNotFound, `{"errors":[]}`},
+		"/v1/ssh/config/ca":              {http.StatusBadRequest, `{"errors":["keys haven't been configured yet"]}`},
+		"/v1/transit/export/hmac-key/xy": {http.StatusBadRequest, `{"errors":["encryption key not found"]}`},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := responses[r.URL.Path]
+		if !ok {
+			res.status = http.StatusInternalServerError
+		}
+
+		w.WriteHeader(res.status)
+		_, _ = w.Write([]byte(res.body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+	client.SetMaxRetries(0)
+
+	_, err = client.KVv1().Read("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.Transit().Read("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.Transit().Export("xy", TransitExportOptions{KeyType: "hmac-key"})
+	require.ErrorIs(t, err, ErrEncKeyNotFound)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.PKI().ReadCertificate("01:02")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.PKI().ReadIssuer("missing")
+	require.ErrorIs(t, err, ErrIssuerNotFound)
+
+	_, err = client.PKI().ReadKey("missing")
+	require.ErrorIs(t, err, ErrPKIKeyNotFound)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.SSH().GetVaultPubKey()
+	require.ErrorIs(t, err, ErrSSHCANotConfigured)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	kv, err := client.KVv1().ReadOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, kv)
+
+	key, err := client.Transit().ReadOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	cert, err := client.PKI().ReadCertificateOrNil("01:02")
+	require.NoError(t, err)
+	require.Nil(t, cert)
+
+	issuer, err := client.PKI().ReadIssuerOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, issuer)
+
+	pkiKey, err := client.PKI().ReadKeyOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, pkiKey)
+
+	pkiRole, err := client.PKI().ReadRoleOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, pkiRole)
+
+	sshRole, err := client.SSH().ReadRoleOrNil("missing")
+	require.NoError(t, err)
+	require.Nil(t, sshRole)
+
+	// other errors are still returned
+	_, err = client.KVv1().ReadOrNil("other")
+	require.Error(t, err)
+	require.False(t, IsNotFound(err))
+}
+
+type failingAuth struct {
+	err error
+}
+
+func (a failingAuth) Auth() (*AuthResponse, error) {
+	return nil, a.err
+}
+
+func TestTokenRenewalError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	loginErr := newError("PUT", "/v1/auth/kubernetes/login", "", &api.ResponseError{StatusCode: http.StatusTooManyRequests})
+	client.auth = failingAuth{err: loginErr}
+
+	err = client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil)
+
+	// the renewal failure is returned as error of the original request, keeping its cause
+	vaultErr := &Error{}
+	require.True(t, errors.As(err, &vaultErr))
+	require.Equal(t, "/v1/kv/key", vaultErr.Path)
+	require.True(t, IsRateLimited(err))
+	require.ErrorIs(t, err, loginErr)
+	require.Contains(t, err.Error(), "token renew after request returned 403 failed")
+}
+
+func TestInvalidResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"), WithNamespace("tenant"))
+	require.NoError(t, err)
+
+	err = client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil)
+
+	vaultErr := &Error{}
+	require.True(t, errors.As(err, &vaultErr))
+	require.Equal(t, "/v1/kv/key", vaultErr.Path)
+	require.Equal(t, "tenant", vaultErr.Namespace)
+	require.Contains(t, err.Error(), "error unmarshalling body into response struct")
+}
@@ -0,0 +1,338 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// CircuitBreaker configures the failover between the addresses passed to NewClient, see WithCircuitBreaker
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures after which no requests are sent to an address,
+	// defaults to 3
+	FailureThreshold int
+	// OpenDuration is the time no requests are sent to a failed address, afterwards its health is checked
+	// again before it's used. Defaults to 30s.
+	OpenDuration time.Duration
+	// HealthCheckInterval is the interval in which the state of an address (active, standby, sealed)
+	// is refreshed, defaults to 30s
+	HealthCheckInterval time.Duration
+}
+
+func DefaultCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold:    3,
+		OpenDuration:        30 * time.Second,
+		HealthCheckInterval: 30 * time.Second,
+	}
+}
+
+type endpointRole int
+
+const (
+	endpointUnknown endpointRole = iota
+	endpointActive
+	endpointPerformanceStandby
+	endpointStandby
+	endpointUnavailable
+)
+
+// endpoint is one of the addresses passed to NewClient
+type endpoint struct {
+	addr *url.URL
+
+	mu        sync.Mutex
+	role      endpointRole
+	checkedAt time.Time
+	failures  int
+	openedAt  time.Time
+
+	// checkMu makes sure only one request checks the health of the endpoint at a time
+	checkMu sync.Mutex
+}
+
+// failover sends requests to the preferred available endpoint. Requests are sent to the first active node
+// in the order of the addresses, reads fall back to performance standbys. Each endpoint has a circuit breaker,
+// which opens after consecutive failures and closes again once a health check of the endpoint succeeds,
+// so a recovered endpoint is preferred again.
+type failover struct {
+	endpoints []*endpoint
+	breaker   CircuitBreaker
+}
+
+// splitAddresses splits the comma separated addresses passed to NewClient
+func splitAddresses(addr string) []string {
+	var addrs []string
+
+	for _, a := range strings.Split(addr, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+
+	return addrs
+}
+
+func newFailover(addrs []string, breaker *CircuitBreaker) (*failover, error) {
+	defaults := DefaultCircuitBreaker()
+	if breaker == nil {
+		breaker = defaults
+	}
+
+	f := &failover{breaker: *breaker}
+
+	if f.breaker.FailureThreshold <= 0 {
+		f.breaker.FailureThreshold = defaults.FailureThreshold
+	}
+	if f.breaker.OpenDuration <= 0 {
+		f.breaker.OpenDuration = defaults.OpenDuration
+	}
+	if f.breaker.HealthCheckInterval <= 0 {
+		f.breaker.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+
+	for _, addr := range addrs {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		f.endpoints = append(f.endpoints, &endpoint{addr: u})
+	}
+
+	return f, nil
+}
+
+// send sends r to the preferred endpoint and fails over to the next one if the request can safely be repeated.
+// client sends the request, health checks are sent by base, which doesn't set a namespace.
+func (f *failover) send(ctx context.Context, client, base *api.Client, r *api.Request, requestPath string, idempotent bool) (*api.Response, error) {
+	read := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == "LIST"
+	tried := map[*endpoint]bool{}
+
+	var resp *api.Response
+	var err error
+
+	for len(tried) < len(f.endpoints) {
+		ep := f.pick(ctx, base, read, tried)
+		if ep == nil {
+			break
+		}
+
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		tried[ep] = true
+		ep.apply(r, requestPath)
+
+		//nolint:staticcheck
+		resp, err = client.RawRequestWithContext(ctx, r)
+		if ctx.Err() != nil {
+			// a canceled request says nothing about the health of ep
+			return resp, err
+		}
+
+		reqErr := newError(r.Method, requestPath, "", err)
+		if !f.record(ep, reqErr) || !IsRetryable(reqErr, idempotent) {
+			return resp, err
+		}
+	}
+
+	if len(tried) == 0 {
+		return nil, ErrUnavailable
+	}
+
+	return resp, err
+}
+
+// pick returns the preferred endpoint not tried yet, nil if the circuit breakers of all endpoints are open
+func (f *failover) pick(ctx context.Context, base *api.Client, read bool, tried map[*endpoint]bool) *endpoint {
+	var standby, fallback *endpoint
+
+	for _, ep := range f.endpoints {
+		if tried[ep] || !f.allow(ep) {
+			continue
+		}
+
+		switch f.check(ctx, base, ep) {
+		case endpointActive:
+			return ep
+		case endpointPerformanceStandby:
+			if read && standby == nil {
+				standby = ep
+			}
+		case endpointUnavailable:
+			continue
+		}
+
+		if fallback == nil {
+			fallback = ep
+		}
+	}
+
+	if standby != nil {
+		return standby
+	}
+
+	// no active node is known, e.g. during a leader election; the request is sent to the first available
+	// endpoint to return the error of vault
+	return fallback
+}
+
+// allow reports whether requests may be sent to ep, which is the case if its circuit breaker is closed
+// or was opened more than OpenDuration ago
+func (f *failover) allow(ep *endpoint) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	return ep.failures < f.breaker.FailureThreshold || time.Since(ep.openedAt) >= f.breaker.OpenDuration
+}
+
+// check returns the role of ep, its health is checked if the last check is older than HealthCheckInterval
+// or ep failed recently. The health check is canceled with ctx, the role is unknown in this case.
+func (f *failover) check(ctx context.Context, base *api.Client, ep *endpoint) endpointRole {
+	stale := func() bool {
+		return ep.failures > 0 || time.Since(ep.checkedAt) >= f.breaker.HealthCheckInterval
+	}
+
+	ep.mu.Lock()
+	if !stale() {
+		defer ep.mu.Unlock()
+		return ep.role
+	}
+	ep.mu.Unlock()
+
+	ep.checkMu.Lock()
+	defer ep.checkMu.Unlock()
+
+	// another request might have checked the endpoint in the meantime
+	ep.mu.Lock()
+	if !stale() {
+		defer ep.mu.Unlock()
+		return ep.role
+	}
+	ep.mu.Unlock()
+
+	role, err := ep.health(ctx, base)
+	if ctx.Err() != nil {
+		return endpointUnknown
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.checkedAt = time.Now()
+	ep.role = role
+
+	if err != nil {
+		ep.role = endpointUnavailable
+	}
+
+	if ep.role == endpointUnavailable {
+		ep.fail(f.breaker.FailureThreshold)
+	} else {
+		ep.failures = 0
+	}
+
+	return ep.role
+}
+
+// record updates the circuit breaker of ep with the result of a request and reports whether ep failed
+func (f *failover) record(ep *endpoint, err *Error) bool {
+	if IsStandby(err) {
+		// the active node changed, all endpoints are checked again before the next request
+		for _, other := range f.endpoints {
+			other.mu.Lock()
+			other.checkedAt = time.Time{}
+			other.mu.Unlock()
+		}
+
+		return true
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	switch {
+	case err.Err == nil:
+		ep.failures = 0
+		return false
+	case IsSealed(err):
+		ep.role = endpointUnavailable
+		ep.fail(f.breaker.FailureThreshold)
+		return true
+	case err.StatusCode == 0, err.StatusCode == http.StatusBadGateway,
+		err.StatusCode == http.StatusServiceUnavailable, err.StatusCode == http.StatusGatewayTimeout:
+		ep.fail(f.breaker.FailureThreshold)
+		return true
+	}
+
+	// other errors are returned by a healthy vault node
+	ep.failures = 0
+
+	return false
+}
+
+// fail records a failure of ep, ep.mu has to be held. The circuit breaker opens after threshold consecutive
+// failures, or immediately if the health check after it was open failed.
+func (ep *endpoint) fail(threshold int) {
+	if ep.failures >= threshold {
+		ep.openedAt = time.Now()
+		return
+	}
+
+	ep.failures++
+	if ep.failures >= threshold {
+		ep.openedAt = time.Now()
+	}
+}
+
+// apply sends r to ep
+func (ep *endpoint) apply(r *api.Request, requestPath string) {
+	r.URL.Scheme = ep.addr.Scheme
+	r.URL.User = ep.addr.User
+	r.URL.Host = ep.addr.Host
+	r.URL.Path = path.Join(ep.addr.Path, requestPath)
+	r.Host = ep.addr.Host
+}
+
+// health returns the role of ep reported by sys/health
+func (ep *endpoint) health(ctx context.Context, base *api.Client) (endpointRole, error) {
+	r := base.NewRequest(http.MethodGet, "/v1/sys/health")
+	ep.apply(r, "/v1/sys/health")
+
+	// sys/health responds with 5xx for sealed and standby nodes by default, which would be returned as error
+	for _, param := range []string{"uninitcode", "sealedcode", "standbycode", "drsecondarycode", "performancestandbycode"} {
+		r.Params.Set(param, "299")
+	}
+
+	//nolint:staticcheck
+	resp, err := base.RawRequestWithContext(ctx, r)
+	if err != nil {
+		return endpointUnknown, err
+	}
+	defer resp.Body.Close()
+
+	health := &api.HealthResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(health); err != nil {
+		return endpointUnknown, err
+	}
+
+	switch {
+	case !health.Initialized || health.Sealed:
+		return endpointUnavailable, nil
+	case health.PerformanceStandby:
+		return endpointPerformanceStandby, nil
+	case health.Standby:
+		return endpointStandby, nil
+	}
+
+	return endpointActive, nil
+}
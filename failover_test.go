@@ -0,0 +1,236 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// testNode is a fake vault node, its state is one of "active", "standby", "perfstandby", "sealed" or "down"
+type testNode struct {
+	mu       sync.Mutex
+	state    string
+	requests int
+	server   *httptest.Server
+}
+
+func newTestNode(state string) *testNode {
+	n := &testNode{state: state}
+
+	n.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.mu.Lock()
+		state := n.state
+		n.mu.Unlock()
+
+		if r.URL.Path == "/v1/sys/health" {
+			if state == "down" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"initialized":         true,
+				"sealed":              state == "sealed",
+				"standby":             strings.HasSuffix(state, "standby"),
+				"performance_standby": state == "perfstandby",
+			})
+
+			return
+		}
+
+		n.mu.Lock()
+		n.requests++
+		n.mu.Unlock()
+
+		switch state {
+		case "down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		case "sealed":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"errors":["Vault is sealed"]}`))
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"node": state}})
+		}
+	}))
+
+	return n
+}
+
+func (n *testNode) set(state string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.state = state
+}
+
+func (n *testNode) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.requests
+}
+
+type FailoverTestSuite struct {
+	suite.Suite
+	nodes []*testNode
+}
+
+func TestFailoverTestSuite(t *testing.T) {
+	suite.Run(t, new(FailoverTestSuite))
+}
+
+func (s *FailoverTestSuite) TearDownTest() {
+	for _, n := range s.nodes {
+		n.server.Close()
+	}
+}
+
+func (s *FailoverTestSuite) client(breaker CircuitBreaker, states ...string) *Client {
+	s.nodes = nil
+
+	var addrs []string
+	for _, state := range states {
+		n := newTestNode(state)
+		s.nodes = append(s.nodes, n)
+		addrs = append(addrs, n.server.URL)
+	}
+
+	client, err := NewClient(strings.Join(addrs, ","), nil, WithAuthToken("token"), WithCircuitBreaker(breaker))
+	require.NoError(s.T(), err)
+
+	return client
+}
+
+func (s *FailoverTestSuite) TestPreferActiveNode() {
+	client := s.client(CircuitBreaker{}, "perfstandby", "active")
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	require.NoError(s.T(), client.Write([]string{"v1", "kv", "key"}, nil, &struct{}{}, nil))
+
+	s.Equal(0, s.nodes[0].count())
+	s.Equal(2, s.nodes[1].count())
+}
+
+func (s *FailoverTestSuite) TestReadsFallBackToPerformanceStandby() {
+	client := s.client(CircuitBreaker{}, "down", "standby", "perfstandby")
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	s.Equal(1, s.nodes[2].count())
+
+	// writes aren't sent to the unavailable node
+	require.NoError(s.T(), client.Write([]string{"v1", "kv", "key"}, nil, &struct{}{}, nil))
+	s.Equal(0, s.nodes[0].count())
+	s.Equal(1, s.nodes[1].count())
+}
+
+func (s *FailoverTestSuite) TestCircuitBreaker() {
+	client := s.client(CircuitBreaker{
+		FailureThreshold:    2,
+		OpenDuration:        100 * time.Millisecond,
+		HealthCheckInterval: time.Hour,
+	}, "active", "active")
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	s.Equal(1, s.nodes[0].count())
+
+	// the failed read is repeated on the second node, the following health check opens the circuit breaker
+	s.nodes[0].set("down")
+	for i := 0; i < 5; i++ {
+		require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	}
+	s.Equal(2, s.nodes[0].count())
+	s.Equal(5, s.nodes[1].count())
+
+	// the first node is preferred again once it recovered
+	s.nodes[0].set("active")
+	time.Sleep(150 * time.Millisecond)
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	s.Equal(3, s.nodes[0].count())
+	s.Equal(5, s.nodes[1].count())
+}
+
+func (s *FailoverTestSuite) TestSealedNode() {
+	client := s.client(CircuitBreaker{HealthCheckInterval: time.Hour}, "active", "active")
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+
+	// sealed nodes didn't process the request, so writes are sent to the next node
+	s.nodes[0].set("sealed")
+	require.NoError(s.T(), client.Write([]string{"v1", "pki", "issue", "role"}, nil, &struct{}{}, nil))
+	s.Equal(2, s.nodes[0].count())
+	s.Equal(1, s.nodes[1].count())
+}
+
+func (s *FailoverTestSuite) TestWritesNotRepeated() {
+	client := s.client(CircuitBreaker{HealthCheckInterval: time.Hour}, "active", "active")
+
+	require.NoError(s.T(), client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+
+	// the node might have processed the write
+	s.nodes[0].set("down")
+	s.Error(client.Write([]string{"v1", "pki", "issue", "role"}, nil, &struct{}{}, nil))
+	s.Equal(0, s.nodes[1].count())
+
+	require.NoError(s.T(), client.Write([]string{"v1", "kv", "key"}, nil, &struct{}{}, &RequestOptions{Idempotent: true}))
+	s.Equal(1, s.nodes[1].count())
+}
+
+func (s *FailoverTestSuite) TestUnavailable() {
+	client := s.client(CircuitBreaker{FailureThreshold: 1}, "down", "down")
+
+	err := client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil)
+	s.True(errors.Is(err, ErrUnavailable))
+	s.True(IsRetryable(err, false))
+}
+
+func TestFailoverConnectionRefused(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	active := newTestNode("active")
+	defer active.server.Close()
+
+	client, err := NewClient(down.URL+", "+active.server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Write([]string{"v1", "pki", "issue", "role"}, nil, &struct{}{}, nil))
+	require.Equal(t, 1, active.count())
+	require.Equal(t, down.URL, client.Address())
+}
+
+func TestFailoverHealthCheckCanceled(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hung.Close()
+	defer close(release)
+
+	active := newTestNode("active")
+	defer active.server.Close()
+
+	client, err := NewClient(hung.URL+","+active.server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.Error(t, client.WithContext(ctx).Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, 0, active.count())
+}
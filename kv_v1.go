@@ -57,6 +57,11 @@ func (k *KVv1) Read(key string) (*KVv1ReadResponse, error) {
 	return readRes, nil
 }
 
+// ReadOrNil is like Read, but returns nil if the key doesn't exist
+func (k *KVv1) ReadOrNil(key string) (*KVv1ReadResponse, error) {
+	return orNil(k.Read(key))
+}
+
 type KVv1ListResponse struct {
 	Data struct {
 		Keys []string `json:"keys"`
@@ -102,3 +102,19 @@ func (s *KVv1TestSuite) TestCreateAndList() {
 	require.Contains(s.T(), nestedList.Data.Keys, "test")
 	require.Len(s.T(), nestedList.Data.Keys, 1)
 }
+
+func (s *KVv1TestSuite) TestReadNotFound() {
+	_, err := s.client.Read("a1b6c9f4-4fd0-4b9a-9a0f-2a7c3f0e5d11")
+	require.ErrorIs(s.T(), err, vault.ErrNotFound)
+	require.True(s.T(), vault.IsNotFound(err))
+
+	res, err := s.client.ReadOrNil("a1b6c9f4-4fd0-4b9a-9a0f-2a7c3f0e5d11")
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), res)
+
+	require.NoError(s.T(), s.client.Create("a1b6c9f4-4fd0-4b9a-9a0f-2a7c3f0e5d11", map[string]string{"key": "value"}))
+
+	res, err = s.client.ReadOrNil("a1b6c9f4-4fd0-4b9a-9a0f-2a7c3f0e5d11")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "value", res.Data["key"])
+}
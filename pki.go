@@ -2,9 +2,6 @@ package vault
 
 import (
 	"errors"
-	"github.com/hashicorp/vault/api"
-	"net/http"
-	"strings"
 )
 
 type PKI struct {
@@ -175,7 +172,9 @@ func (k *PKI) ImportCaOrPrivateKey(pkiopts PKIImportCABundleRequest) (*PKIImport
 type PKIListIssuersResponse struct {
 	Data struct {
 		KeyInfo map[string]struct {
-			IssuerName string `json:"issuer_name"`
+			IssuerName   string `json:"issuer_name"`
+			IsDefault    bool   `json:"is_default"`
+			SerialNumber string `json:"serial_number"`
 		} `json:"key_info"`
 		Keys []string `json:"keys"`
 	} `json:"data"`
@@ -198,10 +197,15 @@ func (k *PKI) ListIssuers() (*PKIListIssuersResponse, error) {
 }
 
 type PKIUpdateIssuerRequest struct {
-	IssuerName           string   `json:"issuer_name"`
-	LeafNotAfterBehavior string   `json:"leaf_not_after_behavior,omitempty"`
-	ManualChain          []string `json:"manual_chain,omitempty"`
-	Usage                []string `json:"usage,omitempty"`
+	IssuerName                   string   `json:"issuer_name"`
+	LeafNotAfterBehavior         string   `json:"leaf_not_after_behavior,omitempty"`
+	ManualChain                  []string `json:"manual_chain,omitempty"`
+	Usage                        []string `json:"usage,omitempty"`
+	RevocationSignatureAlgorithm string   `json:"revocation_signature_algorithm,omitempty"`
+	IssuingCertificates          []string `json:"issuing_certificates,omitempty"`
+	CRLDistributionPoints        []string `json:"crl_distribution_points,omitempty"`
+	OCSPServers                  []string `json:"ocsp_servers,omitempty"`
+	EnableAIAURLTemplating       *bool    `json:"enable_aia_url_templating,omitempty"`
 }
 
 type PKIUpdateIssuerResponse struct {
@@ -241,9 +245,21 @@ func (k *PKI) UpdateIssuer(issuerName string, pkiopts PKIUpdateIssuerRequest) (*
 
 type PKIReadIssuerResponse struct {
 	Data struct {
-		CACertificateChain []string `json:"ca_chain"`
-		Certificate        string   `json:"certificate"`
-		RevocationTime     int      `json:"revocation_time"`
+		CACertificateChain           []string `json:"ca_chain"`
+		Certificate                  string   `json:"certificate"`
+		RevocationTime               int      `json:"revocation_time"`
+		RevocationTimeRFC3339        string   `json:"revocation_time_rfc3339"`
+		Revoked                      bool     `json:"revoked"`
+		IssuerID                     string   `json:"issuer_id"`
+		IssuerName                   string   `json:"issuer_name"`
+		KeyID                        string   `json:"key_id"`
+		LeafNotAfterBehavior         string   `json:"leaf_not_after_behavior"`
+		ManualChain                  []string `json:"manual_chain"`
+		Usage                        string   `json:"usage"`
+		RevocationSignatureAlgorithm string   `json:"revocation_signature_algorithm"`
+		IssuingCertificates          []string `json:"issuing_certificates"`
+		CRLDistributionPoints        []string `json:"crl_distribution_points"`
+		OCSPServers                  []string `json:"ocsp_servers"`
 	} `json:"data"`
 }
 
@@ -265,7 +281,27 @@ func (k *PKI) ReadIssuer(issuerName string) (*PKIReadIssuerResponse, error) {
 	return response, nil
 }
 
+// ReadIssuerOrNil is like ReadIssuer, but returns nil if the issuer doesn't exist
+func (k *PKI) ReadIssuerOrNil(issuerName string) (*PKIReadIssuerResponse, error) {
+	return orNil(k.ReadIssuer(issuerName))
+}
+
 type PKIRevokeIssuerResponse struct {
+	Data struct {
+		CAChain               []string `json:"ca_chain"`
+		Certificate           string   `json:"certificate"`
+		IssuerID              string   `json:"issuer_id"`
+		IssuerName            string   `json:"issuer_name"`
+		KeyID                 string   `json:"key_id"`
+		LeafNotAfterBehavior  string   `json:"leaf_not_after_behavior"`
+		ManualChain           []string `json:"manual_chain"`
+		Usage                 string   `json:"usage"`
+		Revoked               bool     `json:"revoked"`
+		RevocationTime        int64    `json:"revocation_time"`
+		RevocationTimeRFC3339 string   `json:"revocation_time_rfc3339"`
+	} `json:"data"`
+
+	// Deprecated: vault returns the issuer wrapped in data, these fields are never set. Use Data instead.
 	CAChain              []string    `json:"ca_chain"`
 	Certificate          string      `json:"certificate"`
 	IssuerID             string      `json:"issuer_id"`
@@ -296,18 +332,106 @@ func (k *PKI) RevokeIssuer(issuerName string) (*PKIRevokeIssuerResponse, error)
 }
 
 type PKICreateRoleRequest struct {
-	IssuerRef        string   `json:"issuer_ref"`
-	TTL              string   `json:"ttl,omitempty"`
-	MaxTTL           string   `json:"max_ttl,omitempty"`
-	AllowedDomains   []string `json:"allowed_domains,omitempty"`
-	AllowBareDomain  bool     `json:"allow_bare_domain,omitempty"`
-	AllowGlobDomains bool     `json:"allow_glob_domains,omitempty"`
-	AllowWildcard    bool     `json:"allow_wildcard_certificates,omitempty"`
-	AllowSubdomains  bool     `json:"allow_subdomains,omitempty"`
-	ServerFlag       bool     `json:"server_flag,omitempty"`
+	IssuerRef                     string   `json:"issuer_ref,omitempty"`
+	TTL                           string   `json:"ttl,omitempty"`
+	MaxTTL                        string   `json:"max_ttl,omitempty"`
+	AllowedDomains                []string `json:"allowed_domains,omitempty"`
+	AllowBareDomain               bool     `json:"allow_bare_domains,omitempty"`
+	AllowGlobDomains              bool     `json:"allow_glob_domains,omitempty"`
+	AllowWildcard                 bool     `json:"allow_wildcard_certificates,omitempty"`
+	AllowSubdomains               bool     `json:"allow_subdomains,omitempty"`
+	ServerFlag                    bool     `json:"server_flag,omitempty"`
+	AllowLocalhost                *bool    `json:"allow_localhost,omitempty"`
+	AllowedDomainsTemplate        *bool    `json:"allowed_domains_template,omitempty"`
+	AllowAnyName                  *bool    `json:"allow_any_name,omitempty"`
+	EnforceHostnames              *bool    `json:"enforce_hostnames,omitempty"`
+	AllowIPSans                   *bool    `json:"allow_ip_sans,omitempty"`
+	AllowedURISans                []string `json:"allowed_uri_sans,omitempty"`
+	AllowedURISansTemplate        *bool    `json:"allowed_uri_sans_template,omitempty"`
+	AllowedOtherSans              []string `json:"allowed_other_sans,omitempty"`
+	AllowedSerialNumbers          []string `json:"allowed_serial_numbers,omitempty"`
+	ClientFlag                    *bool    `json:"client_flag,omitempty"`
+	CodeSigningFlag               *bool    `json:"code_signing_flag,omitempty"`
+	EmailProtectionFlag           *bool    `json:"email_protection_flag,omitempty"`
+	KeyType                       string   `json:"key_type,omitempty"`
+	KeyBits                       int      `json:"key_bits,omitempty"`
+	SignatureBits                 int      `json:"signature_bits,omitempty"`
+	UsePSS                        *bool    `json:"use_pss,omitempty"`
+	KeyUsage                      []string `json:"key_usage,omitempty"`
+	ExtKeyUsage                   []string `json:"ext_key_usage,omitempty"`
+	ExtKeyUsageOIDs               []string `json:"ext_key_usage_oids,omitempty"`
+	UseCSRCommonName              *bool    `json:"use_csr_common_name,omitempty"`
+	UseCSRSans                    *bool    `json:"use_csr_sans,omitempty"`
+	OU                            []string `json:"ou,omitempty"`
+	Organization                  []string `json:"organization,omitempty"`
+	Country                       []string `json:"country,omitempty"`
+	Locality                      []string `json:"locality,omitempty"`
+	Province                      []string `json:"province,omitempty"`
+	StreetAddress                 []string `json:"street_address,omitempty"`
+	PostalCode                    []string `json:"postal_code,omitempty"`
+	GenerateLease                 *bool    `json:"generate_lease,omitempty"`
+	NoStore                       *bool    `json:"no_store,omitempty"`
+	RequireCN                     *bool    `json:"require_cn,omitempty"`
+	PolicyIdentifiers             []string `json:"policy_identifiers,omitempty"`
+	BasicConstraintsValidForNonCA *bool    `json:"basic_constraints_valid_for_non_ca,omitempty"`
+	NotBeforeDuration             string   `json:"not_before_duration,omitempty"`
+	NotAfter                      string   `json:"not_after,omitempty"`
+	CNValidations                 []string `json:"cn_validations,omitempty"`
+	AllowedUserIDs                []string `json:"allowed_user_ids,omitempty"`
 }
 
 type PKIRoleResponse struct {
+	Data struct {
+		AllowAnyName                  bool     `json:"allow_any_name"`
+		AllowBareDomains              bool     `json:"allow_bare_domains"`
+		AllowGlobDomains              bool     `json:"allow_glob_domains"`
+		AllowIPSans                   bool     `json:"allow_ip_sans"`
+		AllowLocalhost                bool     `json:"allow_localhost"`
+		AllowSubdomains               bool     `json:"allow_subdomains"`
+		AllowTokenDisplayname         bool     `json:"allow_token_displayname"`
+		AllowWildcardCertificates     bool     `json:"allow_wildcard_certificates"`
+		AllowedDomains                []string `json:"allowed_domains"`
+		AllowedDomainsTemplate        bool     `json:"allowed_domains_template"`
+		AllowedOtherSans              []string `json:"allowed_other_sans"`
+		AllowedSerialNumbers          []string `json:"allowed_serial_numbers"`
+		AllowedURISans                []string `json:"allowed_uri_sans"`
+		AllowedURISansTemplate        bool     `json:"allowed_uri_sans_template"`
+		AllowedUserIDs                []string `json:"allowed_user_ids"`
+		BasicConstraintsValidForNonCA bool     `json:"basic_constraints_valid_for_non_ca"`
+		ClientFlag                    bool     `json:"client_flag"`
+		CNValidations                 []string `json:"cn_validations"`
+		CodeSigningFlag               bool     `json:"code_signing_flag"`
+		Country                       []string `json:"country"`
+		EmailProtectionFlag           bool     `json:"email_protection_flag"`
+		EnforceHostnames              bool     `json:"enforce_hostnames"`
+		ExtKeyUsage                   []string `json:"ext_key_usage"`
+		ExtKeyUsageOIDs               []string `json:"ext_key_usage_oids"`
+		GenerateLease                 bool     `json:"generate_lease"`
+		IssuerRef                     string   `json:"issuer_ref"`
+		KeyBits                       int      `json:"key_bits"`
+		KeyType                       string   `json:"key_type"`
+		KeyUsage                      []string `json:"key_usage"`
+		Locality                      []string `json:"locality"`
+		MaxTTL                        int      `json:"max_ttl"`
+		NoStore                       bool     `json:"no_store"`
+		NotAfter                      string   `json:"not_after"`
+		NotBeforeDuration             int      `json:"not_before_duration"`
+		Organization                  []string `json:"organization"`
+		OU                            []string `json:"ou"`
+		PolicyIdentifiers             []string `json:"policy_identifiers"`
+		PostalCode                    []string `json:"postal_code"`
+		Province                      []string `json:"province"`
+		RequireCN                     bool     `json:"require_cn"`
+		ServerFlag                    bool     `json:"server_flag"`
+		SignatureBits                 int      `json:"signature_bits"`
+		StreetAddress                 []string `json:"street_address"`
+		TTL                           int      `json:"ttl"`
+		UseCSRCommonName              bool     `json:"use_csr_common_name"`
+		UseCSRSans                    bool     `json:"use_csr_sans"`
+		UsePSS                        bool     `json:"use_pss"`
+	} `json:"data"`
+
+	// Deprecated: vault returns the role wrapped in data, these fields are never set. Use Data instead.
 	AllowAnyName              bool     `json:"allow_any_name"`
 	AllowBareDomains          bool     `json:"allow_bare_domains"`
 	AllowGlobDomains          bool     `json:"allow_glob_domains"`
@@ -369,6 +493,47 @@ func (k *PKI) ReadRole(roleName string) (*PKIRoleResponse, error) {
 	return response, nil
 }
 
+// ReadRoleOrNil is like ReadRole, but returns nil if the role doesn't exist
+func (k *PKI) ReadRoleOrNil(roleName string) (*PKIRoleResponse, error) {
+	return orNil(k.ReadRole(roleName))
+}
+
+type PKIListRolesResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+func (k *PKI) ListRoles() (*PKIListRolesResponse, error) {
+	response := &PKIListRolesResponse{}
+	err := k.client.List(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+		}, nil, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (k *PKI) DeleteRole(roleName string) error {
+	err := k.client.Delete(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+			roleName,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 type PKISignCSROptions struct {
 	CSR        string `json:"csr"`
 	CommonName string `json:"common_name,omitempty"`
@@ -408,13 +573,17 @@ func (k *PKI) SignCSR(name string, issuerRef string, pkiopts PKISignCSROptions)
 }
 
 func (k *PKI) mapError(err error) error {
-	resErr := &api.ResponseError{}
-	if errors.As(err, &resErr) {
-		if resErr.StatusCode == http.StatusInternalServerError {
-			if strings.Contains(err.Error(), "unable to find PKI issuer for reference") {
-				return ErrIssuerNotFound
-			}
-		}
+	vaultErr := &Error{}
+	if !errors.As(err, &vaultErr) {
+		return err
+	}
+
+	// unknown issuer and key references are reported with status 500 or, depending on the vault version, 400
+	switch {
+	case vaultErr.hasMessage("unable to find pki issuer for reference"):
+		return vaultErr.withErr(ErrIssuerNotFound)
+	case vaultErr.hasMessage("unable to find pki key for reference"):
+		return vaultErr.withErr(ErrPKIKeyNotFound)
 	}
 
 	return err
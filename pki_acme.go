@@ -0,0 +1,277 @@
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/acme"
+)
+
+// PKIACMEDirectoryURL returns the ACME directory of a PKI mount. If role is set, the directory of the
+// role is returned, certificates are issued according to this role then.
+func PKIACMEDirectoryURL(address string, mountPoint string, role string) string {
+	path := []string{"v1", mountPoint}
+
+	if role != "" {
+		path = append(path, "roles", role)
+	}
+
+	return strings.TrimRight(address, "/") + resolvePath(append(path, "acme", "directory"))
+}
+
+type PKINewEABResponse struct {
+	Data struct {
+		ID            string `json:"id"`
+		KeyType       string `json:"key_type"`
+		Key           string `json:"key"`
+		ACMEDirectory string `json:"acme_directory"`
+		CreatedOn     string `json:"created_on"`
+	} `json:"data"`
+}
+
+// ExternalAccountBinding returns the binding to use with PKIACMEClient.Register
+func (r *PKINewEABResponse) ExternalAccountBinding() (*acme.ExternalAccountBinding, error) {
+	key, err := base64.RawURLEncoding.DecodeString(r.Data.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode eab key")
+	}
+
+	return &acme.ExternalAccountBinding{
+		KID: r.Data.ID,
+		Key: key,
+	}, nil
+}
+
+// NewACMEEAB creates external account binding credentials, which can be handed to workloads
+// registering an ACME account without a vault token. If role is set, the binding is restricted to its directory.
+func (k *PKI) NewACMEEAB(role string) (*PKINewEABResponse, error) {
+	path := []string{"v1", k.MountPoint}
+
+	if role != "" {
+		path = append(path, "roles", role)
+	}
+
+	response := &PKINewEABResponse{}
+	err := k.client.Write(append(path, "acme", "new-eab"), nil, response, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func NewPKIACMEClient(directoryURL string, opts ...PKIACMEOpt) (*PKIACMEClient, error) {
+	c := &PKIACMEClient{
+		client: &acme.Client{
+			DirectoryURL: directoryURL,
+		},
+		tokens: make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.client.Key == nil {
+		key, err := GeneratePrivateKey("ec", 256)
+		if err != nil {
+			return nil, err
+		}
+
+		c.client.Key = key
+	}
+
+	return c, nil
+}
+
+// PKIACMEClient obtains certificates from the ACME server of a PKI mount using http-01 challenges.
+// It doesn't require a vault token, the challenges have to be served by the handler returned by HTTPHandler.
+type PKIACMEClient struct {
+	client  *acme.Client
+	contact []string
+
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+type PKIACMEOpt func(c *PKIACMEClient) error
+
+// WithACMEAccountKey sets the key of the ACME account, a new EC key is generated otherwise
+func WithACMEAccountKey(key crypto.Signer) PKIACMEOpt {
+	return func(c *PKIACMEClient) error {
+		c.client.Key = key
+
+		return nil
+	}
+}
+
+// WithACMEHTTPClient sets the client used to talk to vault, e.g. to trust the vault CA
+func WithACMEHTTPClient(client *http.Client) PKIACMEOpt {
+	return func(c *PKIACMEClient) error {
+		c.client.HTTPClient = client
+
+		return nil
+	}
+}
+
+// WithACMEContact sets the contact addresses (e.g. "mailto:ops@example.com") of the account
+func WithACMEContact(contact ...string) PKIACMEOpt {
+	return func(c *PKIACMEClient) error {
+		c.contact = contact
+
+		return nil
+	}
+}
+
+// Register creates the ACME account, eab is required if the mount enforces external account bindings.
+// Registering an account which already exists is not an error.
+func (c *PKIACMEClient) Register(ctx context.Context, eab *acme.ExternalAccountBinding) (*acme.Account, error) {
+	account, err := c.client.Register(ctx, &acme.Account{
+		Contact:                c.contact,
+		ExternalAccountBinding: eab,
+	}, acme.AcceptTOS)
+	if errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return c.client.GetReg(ctx, "")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register acme account")
+	}
+
+	return account, nil
+}
+
+// HTTPHandler serves http-01 challenges and passes all other requests to next, which may be nil.
+// It has to be reachable by vault on port 80 of every requested identifier.
+func (c *PKIACMEClient) HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		response, ok := c.tokens[r.URL.Path]
+		c.mu.RUnlock()
+
+		if ok && r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(response))
+
+			return
+		}
+
+		if next == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ObtainCertificate orders a certificate for the DNS names and IP addresses of template, solves the http-01
+// challenges, finalizes the order with a CSR signed by key and returns the certificate including its chain.
+func (c *PKIACMEClient) ObtainCertificate(ctx context.Context, key crypto.Signer, template *x509.CertificateRequest) (*tls.Certificate, error) {
+	ids := acme.DomainIDs(template.DNSNames...)
+	for _, ip := range template.IPAddresses {
+		ids = append(ids, acme.IPIDs(ip.String())...)
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no identifiers requested")
+	}
+
+	order, err := c.client.AuthorizeOrder(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create acme order")
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := c.authorize(ctx, authzURL); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err = c.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, errors.Wrap(err, "acme order failed")
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate request")
+	}
+
+	der, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to finalize acme order")
+	}
+
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: der,
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+func (c *PKIACMEClient) authorize(ctx context.Context, authzURL string) error {
+	authz, err := c.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to get acme authorization")
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, ch := range authz.Challenges {
+		if ch.Type == "http-01" {
+			challenge = ch
+			break
+		}
+	}
+
+	if challenge == nil {
+		return fmt.Errorf("no http-01 challenge offered for %s", authz.Identifier.Value)
+	}
+
+	response, err := c.client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	path := c.client.HTTP01ChallengePath(challenge.Token)
+
+	c.mu.Lock()
+	c.tokens[path] = response
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.tokens, path)
+		c.mu.Unlock()
+	}()
+
+	if _, err := c.client.Accept(ctx, challenge); err != nil {
+		return errors.Wrap(err, "failed to accept acme challenge")
+	}
+
+	if _, err := c.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return errors.Wrapf(err, "acme authorization for %s failed", authz.Identifier.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKIACMETestSuite struct {
+	suite.Suite
+}
+
+func TestPKIACMETestSuite(t *testing.T) {
+	suite.Run(t, new(PKIACMETestSuite))
+}
+
+func (s *PKIACMETestSuite) TestDirectoryURL() {
+	s.Equal("https://vault:8200/v1/pki/acme/directory", PKIACMEDirectoryURL("https://vault:8200/", "pki", ""))
+	s.Equal("https://vault:8200/v1/pki_int/roles/web/acme/directory", PKIACMEDirectoryURL("https://vault:8200", "/pki_int/", "web"))
+}
+
+func (s *PKIACMETestSuite) TestExternalAccountBinding() {
+	res := &PKINewEABResponse{}
+	res.Data.ID = "kid"
+	res.Data.Key = "dGVzdA"
+
+	eab, err := res.ExternalAccountBinding()
+	require.NoError(s.T(), err)
+	s.Equal("kid", eab.KID)
+	s.Equal([]byte("test"), eab.Key)
+}
+
+func (s *PKIACMETestSuite) TestHTTPHandler() {
+	c, err := NewPKIACMEClient("https://vault:8200/v1/pki/acme/directory")
+	require.NoError(s.T(), err)
+
+	c.tokens["/.well-known/acme-challenge/token"] = "token.thumbprint"
+
+	handler := c.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/acme-challenge/token", nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("token.thumbprint", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	s.Equal(http.StatusTeapot, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c.HTTPHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/acme-challenge/unknown", nil))
+	s.Equal(http.StatusNotFound, rec.Code)
+}
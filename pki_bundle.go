@@ -0,0 +1,160 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PKITrustBundle holds all issuers of a PKI mount, see PKI.TrustBundle
+type PKITrustBundle struct {
+	Issuers []PKITrustBundleIssuer
+}
+
+type PKITrustBundleIssuer struct {
+	IssuerID    string
+	IssuerName  string
+	Usage       []string
+	Certificate *x509.Certificate
+	Revoked     bool
+	// ChainError is set if the CA chain of the issuer could not be verified
+	ChainError error
+	Expired    bool
+	// ExpiresSoon is set if the certificate expires within the warning period passed to PKI.TrustBundle
+	ExpiresSoon bool
+}
+
+// TrustBundle reads all issuers of the mount and verifies their CA chains.
+// Issuers expiring within expiryWarning are flagged with ExpiresSoon.
+func (k *PKI) TrustBundle(expiryWarning time.Duration) (*PKITrustBundle, error) {
+	issuers, err := k.ListIssuers()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bundle := &PKITrustBundle{}
+
+	for _, id := range issuers.Data.Keys {
+		issuer, err := k.ReadIssuer(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read issuer %s", id)
+		}
+
+		cert, err := ParseCertificate(issuer.Data.Certificate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse certificate of issuer %s", id)
+		}
+
+		entry := PKITrustBundleIssuer{
+			IssuerID:    id,
+			IssuerName:  issuers.Data.KeyInfo[id].IssuerName,
+			Certificate: cert,
+			Revoked:     issuer.Data.Revoked || issuer.Data.RevocationTime > 0,
+			Expired:     now.After(cert.NotAfter),
+			ExpiresSoon: now.Add(expiryWarning).After(cert.NotAfter),
+		}
+
+		if issuer.Data.Usage != "" {
+			entry.Usage = strings.Split(issuer.Data.Usage, ",")
+		}
+
+		chain, err := parseCertificateChain(issuer.Data.CACertificateChain)
+		if err != nil {
+			entry.ChainError = err
+		} else {
+			entry.ChainError = verifyIssuerChain(cert, chain)
+		}
+
+		bundle.Issuers = append(bundle.Issuers, entry)
+	}
+
+	return bundle, nil
+}
+
+// Certificates returns the de-duplicated certificates of all valid issuers having the given usage
+// (e.g. "issuing-certificates" or "crl-signing"), or of all valid issuers if usage is empty.
+// Issuers which are revoked, expired or have an invalid CA chain are skipped.
+func (b *PKITrustBundle) Certificates(usage string) []*x509.Certificate {
+	var certs []*x509.Certificate
+
+	seen := make(map[string]bool)
+
+	for _, issuer := range b.Issuers {
+		if issuer.Revoked || issuer.Expired || issuer.ChainError != nil {
+			continue
+		}
+
+		if usage != "" && !containsString(issuer.Usage, usage) {
+			continue
+		}
+
+		if seen[string(issuer.Certificate.Raw)] {
+			continue
+		}
+		seen[string(issuer.Certificate.Raw)] = true
+
+		certs = append(certs, issuer.Certificate)
+	}
+
+	return certs
+}
+
+// PEM returns the PEM encoded bundle of Certificates(usage)
+func (b *PKITrustBundle) PEM(usage string) []byte {
+	buf := &bytes.Buffer{}
+
+	for _, cert := range b.Certificates(usage) {
+		_ = pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	return buf.Bytes()
+}
+
+// CertPool returns a pool containing Certificates(usage)
+func (b *PKITrustBundle) CertPool(usage string) *x509.CertPool {
+	pool := x509.NewCertPool()
+
+	for _, cert := range b.Certificates(usage) {
+		pool.AddCert(cert)
+	}
+
+	return pool
+}
+
+// WriteFile atomically writes PEM(usage) to path, the file is only replaced if its content changed
+func (b *PKITrustBundle) WriteFile(path string, usage string, perm os.FileMode) (bool, error) {
+	return writeFileAtomic(path, b.PEM(usage), perm)
+}
+
+// verifyIssuerChain verifies the CA chain returned for an issuer, which starts with the issuer itself
+func verifyIssuerChain(cert *x509.Certificate, chain []*x509.Certificate) error {
+	if len(chain) == 0 || !chain[0].Equal(cert) {
+		return errors.New("CA chain doesn't start with the issuer certificate")
+	}
+
+	if len(chain) == 1 {
+		if err := cert.CheckSignatureFrom(cert); err != nil {
+			return errors.Wrap(err, "CA chain is incomplete")
+		}
+
+		return nil
+	}
+
+	return verifyCertificateChain(cert, chain[1:])
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
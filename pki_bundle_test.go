@@ -0,0 +1,114 @@
+package vault
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKIBundleTestSuite struct {
+	suite.Suite
+	root         *testCertificate
+	intermediate *testCertificate
+	expiring     *testCertificate
+	foreign      *testCertificate
+	pki          *PKI
+	server       *httptest.Server
+}
+
+func TestPKIBundleTestSuite(t *testing.T) {
+	suite.Run(t, new(PKIBundleTestSuite))
+}
+
+func (s *PKIBundleTestSuite) SetupTest() {
+	s.root = newTestCertificate(s.T(), "root", true, time.Now().Add(24*time.Hour), nil)
+	s.intermediate = newTestCertificate(s.T(), "intermediate", true, time.Now().Add(12*time.Hour), s.root)
+	s.expiring = newTestCertificate(s.T(), "expiring", true, time.Now().Add(time.Hour), s.root)
+	s.foreign = newTestCertificate(s.T(), "foreign", true, time.Now().Add(time.Hour), nil)
+
+	issuers := map[string]map[string]interface{}{
+		"root":         {"certificate": s.root.pem, "ca_chain": []string{s.root.pem}, "usage": "read-only,issuing-certificates,crl-signing"},
+		"root-copy":    {"certificate": s.root.pem, "ca_chain": []string{s.root.pem}, "usage": "read-only,issuing-certificates,crl-signing"},
+		"intermediate": {"certificate": s.intermediate.pem, "ca_chain": []string{s.intermediate.pem, s.root.pem}, "usage": "read-only,crl-signing"},
+		"expiring":     {"certificate": s.expiring.pem, "ca_chain": []string{s.expiring.pem, s.root.pem}, "usage": "read-only,issuing-certificates"},
+		// chain doesn't verify
+		"broken": {"certificate": s.intermediate.pem, "ca_chain": []string{s.intermediate.pem, s.foreign.pem}, "usage": "read-only"},
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+
+		switch {
+		case r.URL.Path == "/v1/pki/issuers":
+			data = map[string]interface{}{"keys": []string{"root", "root-copy", "intermediate", "expiring", "broken"}}
+		case strings.HasPrefix(r.URL.Path, "/v1/pki/issuer/"):
+			data = issuers[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/pki/issuer/"), "/json")]
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+
+	client, err := NewClient(s.server.URL, nil)
+	require.NoError(s.T(), err)
+	s.pki = client.PKI()
+}
+
+func (s *PKIBundleTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *PKIBundleTestSuite) TestTrustBundle() {
+	bundle, err := s.pki.TrustBundle(2 * time.Hour)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), bundle.Issuers, 5)
+
+	for _, issuer := range bundle.Issuers {
+		switch issuer.IssuerID {
+		case "broken":
+			s.Error(issuer.ChainError)
+		case "expiring":
+			s.NoError(issuer.ChainError)
+			s.True(issuer.ExpiresSoon)
+		default:
+			s.NoError(issuer.ChainError, issuer.IssuerID)
+			s.False(issuer.ExpiresSoon, issuer.IssuerID)
+		}
+	}
+
+	s.Equal([]*x509.Certificate{s.root.cert, s.intermediate.cert, s.expiring.cert}, bundle.Certificates(""))
+	s.Equal([]*x509.Certificate{s.root.cert, s.expiring.cert}, bundle.Certificates("issuing-certificates"))
+	s.Equal([]*x509.Certificate{s.root.cert, s.intermediate.cert}, bundle.Certificates("crl-signing"))
+
+	_, err = s.intermediate.cert.Verify(x509.VerifyOptions{Roots: bundle.CertPool("issuing-certificates")})
+	s.NoError(err)
+
+	s.Equal(s.root.pem+s.expiring.pem, string(bundle.PEM("issuing-certificates")))
+}
+
+func (s *PKIBundleTestSuite) TestWriteFile() {
+	bundle, err := s.pki.TrustBundle(0)
+	require.NoError(s.T(), err)
+
+	path := filepath.Join(s.T().TempDir(), "ca.pem")
+
+	changed, err := bundle.WriteFile(path, "", 0o644)
+	require.NoError(s.T(), err)
+	s.True(changed)
+
+	changed, err = bundle.WriteFile(path, "", 0o644)
+	require.NoError(s.T(), err)
+	s.False(changed)
+
+	content, err := os.ReadFile(path)
+	require.NoError(s.T(), err)
+	s.Equal(bundle.PEM(""), content)
+}
@@ -0,0 +1,257 @@
+package vault
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+type PKIListCertificatesResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+// ListCertificates lists the serial numbers of all certificates issued by this mount
+func (k *PKI) ListCertificates() (*PKIListCertificatesResponse, error) {
+	return k.listCertificates([]string{"v1", k.MountPoint, "certs"})
+}
+
+// ListRevoked lists the serial numbers of all revoked certificates
+func (k *PKI) ListRevoked() (*PKIListCertificatesResponse, error) {
+	return k.listCertificates([]string{"v1", k.MountPoint, "certs", "revoked"})
+}
+
+func (k *PKI) listCertificates(path []string) (*PKIListCertificatesResponse, error) {
+	response := &PKIListCertificatesResponse{}
+	err := k.client.List(path, nil, response, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIReadCertificateResponse struct {
+	Data struct {
+		Certificate           string   `json:"certificate"`
+		CAChain               []string `json:"ca_chain"`
+		IssuerID              string   `json:"issuer_id"`
+		RevocationTime        int64    `json:"revocation_time"`
+		RevocationTimeRFC3339 string   `json:"revocation_time_rfc3339"`
+	} `json:"data"`
+
+	// ParsedCertificate is the parsed Data.Certificate
+	ParsedCertificate *x509.Certificate `json:"-"`
+}
+
+// ReadCertificate reads the certificate with the given serial number (in the "xx:xx:xx" or "xx-xx-xx" format)
+func (k *PKI) ReadCertificate(serialNumber string) (*PKIReadCertificateResponse, error) {
+	response := &PKIReadCertificateResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"cert",
+			serialNumber,
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// some vault versions respond to unknown serial numbers with an empty certificate
+	if response.Data.Certificate == "" {
+		return nil, fmt.Errorf("certificate %s: %w", serialNumber, ErrNotFound)
+	}
+
+	response.ParsedCertificate, err = ParseCertificate(response.Data.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ReadCertificateOrNil is like ReadCertificate, but returns nil if the certificate doesn't exist
+func (k *PKI) ReadCertificateOrNil(serialNumber string) (*PKIReadCertificateResponse, error) {
+	return orNil(k.ReadCertificate(serialNumber))
+}
+
+type PKITidyRequest struct {
+	TidyCertStore                     *bool  `json:"tidy_cert_store,omitempty"`
+	TidyRevokedCerts                  *bool  `json:"tidy_revoked_certs,omitempty"`
+	TidyRevokedCertIssuerAssociations *bool  `json:"tidy_revoked_cert_issuer_associations,omitempty"`
+	TidyExpiredIssuers                *bool  `json:"tidy_expired_issuers,omitempty"`
+	TidyMoveLegacyCABundle            *bool  `json:"tidy_move_legacy_ca_bundle,omitempty"`
+	SafetyBuffer                      string `json:"safety_buffer,omitempty"`
+	IssuerSafetyBuffer                string `json:"issuer_safety_buffer,omitempty"`
+	PauseDuration                     string `json:"pause_duration,omitempty"`
+}
+
+// Tidy starts a tidy operation in the background, use TidyStatus to follow its progress
+func (k *PKI) Tidy(pkiopts PKITidyRequest) error {
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"tidy",
+		}, pkiopts, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type PKITidyStatusResponse struct {
+	Data struct {
+		SafetyBuffer                      int    `json:"safety_buffer"`
+		IssuerSafetyBuffer                int    `json:"issuer_safety_buffer"`
+		TidyCertStore                     bool   `json:"tidy_cert_store"`
+		TidyRevokedCerts                  bool   `json:"tidy_revoked_certs"`
+		TidyRevokedCertIssuerAssociations bool   `json:"tidy_revoked_cert_issuer_associations"`
+		TidyExpiredIssuers                bool   `json:"tidy_expired_issuers"`
+		TidyMoveLegacyCABundle            bool   `json:"tidy_move_legacy_ca_bundle"`
+		PauseDuration                     string `json:"pause_duration"`
+		State                             string `json:"state"`
+		Error                             string `json:"error"`
+		TimeStarted                       string `json:"time_started"`
+		TimeFinished                      string `json:"time_finished"`
+		Message                           string `json:"message"`
+		CertStoreDeletedCount             int    `json:"cert_store_deleted_count"`
+		RevokedCertDeletedCount           int    `json:"revoked_cert_deleted_count"`
+		MissingIssuerCertCount            int    `json:"missing_issuer_cert_count"`
+		CurrentCertStoreCount             int    `json:"current_cert_store_count"`
+		CurrentRevokedCertCount           int    `json:"current_revoked_cert_count"`
+	} `json:"data"`
+}
+
+func (k *PKI) TidyStatus() (*PKITidyStatusResponse, error) {
+	response := &PKITidyStatusResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"tidy-status",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// TidyCancel requests the running tidy operation to stop after processing the current certificate
+func (k *PKI) TidyCancel() (*PKITidyStatusResponse, error) {
+	response := &PKITidyStatusResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"tidy-cancel",
+		}, nil, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIAutoTidyConfigRequest struct {
+	PKITidyRequest
+	Enabled          *bool  `json:"enabled,omitempty"`
+	IntervalDuration string `json:"interval_duration,omitempty"`
+}
+
+type PKIAutoTidyConfigResponse struct {
+	Data struct {
+		Enabled                           bool   `json:"enabled"`
+		IntervalDuration                  int    `json:"interval_duration"`
+		TidyCertStore                     bool   `json:"tidy_cert_store"`
+		TidyRevokedCerts                  bool   `json:"tidy_revoked_certs"`
+		TidyRevokedCertIssuerAssociations bool   `json:"tidy_revoked_cert_issuer_associations"`
+		TidyExpiredIssuers                bool   `json:"tidy_expired_issuers"`
+		TidyMoveLegacyCABundle            bool   `json:"tidy_move_legacy_ca_bundle"`
+		SafetyBuffer                      int    `json:"safety_buffer"`
+		IssuerSafetyBuffer                int    `json:"issuer_safety_buffer"`
+		PauseDuration                     string `json:"pause_duration"`
+	} `json:"data"`
+}
+
+func (k *PKI) ReadAutoTidyConfig() (*PKIAutoTidyConfigResponse, error) {
+	response := &PKIAutoTidyConfigResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"auto-tidy",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) UpdateAutoTidyConfig(pkiopts PKIAutoTidyConfigRequest) (*PKIAutoTidyConfigResponse, error) {
+	response := &PKIAutoTidyConfigResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"auto-tidy",
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ParseCertificate parses the first certificate of a PEM encoded string
+func ParseCertificate(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parseCertificateChain parses all certificates of the given PEM encoded strings, keeping their order
+func parseCertificateChain(pems []string) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+
+	for _, p := range pems {
+		rest := []byte(p)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+
+			chain = append(chain, cert)
+		}
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,100 @@
+package vault
+
+type PKIURLsConfigRequest struct {
+	IssuingCertificates        []string `json:"issuing_certificates,omitempty"`
+	CRLDistributionPoints      []string `json:"crl_distribution_points,omitempty"`
+	DeltaCRLDistributionPoints []string `json:"delta_crl_distribution_points,omitempty"`
+	OCSPServers                []string `json:"ocsp_servers,omitempty"`
+	// EnableTemplating allows using {{cluster_path}}, {{cluster_aia_path}} and {{issuer_id}} in the URLs
+	EnableTemplating *bool `json:"enable_templating,omitempty"`
+}
+
+type PKIURLsConfigResponse struct {
+	Data struct {
+		IssuingCertificates        []string `json:"issuing_certificates"`
+		CRLDistributionPoints      []string `json:"crl_distribution_points"`
+		DeltaCRLDistributionPoints []string `json:"delta_crl_distribution_points"`
+		OCSPServers                []string `json:"ocsp_servers"`
+		EnableTemplating           bool     `json:"enable_templating"`
+	} `json:"data"`
+}
+
+// ReadURLsConfig reads the AIA URLs encoded into issued certificates
+func (k *PKI) ReadURLsConfig() (*PKIURLsConfigResponse, error) {
+	response := &PKIURLsConfigResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"urls",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) UpdateURLsConfig(pkiopts PKIURLsConfigRequest) error {
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"urls",
+		}, pkiopts, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type PKIClusterConfigRequest struct {
+	Path    string `json:"path,omitempty"`
+	AIAPath string `json:"aia_path,omitempty"`
+}
+
+type PKIClusterConfigResponse struct {
+	Data struct {
+		Path    string `json:"path"`
+		AIAPath string `json:"aia_path"`
+	} `json:"data"`
+}
+
+// ReadClusterConfig reads the per-cluster paths used for templated AIA URLs
+func (k *PKI) ReadClusterConfig() (*PKIClusterConfigResponse, error) {
+	response := &PKIClusterConfigResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"cluster",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) UpdateClusterConfig(pkiopts PKIClusterConfigRequest) error {
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"cluster",
+		}, pkiopts, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package vault
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+)
+
+type PKIRevokeRequest struct {
+	SerialNumber string `json:"serial_number,omitempty"`
+	Certificate  string `json:"certificate,omitempty"`
+}
+
+type PKIRevokeWithKeyRequest struct {
+	SerialNumber string `json:"serial_number,omitempty"`
+	Certificate  string `json:"certificate,omitempty"`
+	PrivateKey   string `json:"private_key"`
+}
+
+type PKIRevokeResponse struct {
+	Data struct {
+		RevocationTime        int64  `json:"revocation_time"`
+		RevocationTimeRFC3339 string `json:"revocation_time_rfc3339"`
+		State                 string `json:"state"`
+	} `json:"data"`
+}
+
+// Revoke revokes the certificate with the given serial number (in the "xx:xx:xx" or "xx-xx-xx" format)
+func (k *PKI) Revoke(serialNumber string) (*PKIRevokeResponse, error) {
+	return k.revoke("revoke", PKIRevokeRequest{SerialNumber: serialNumber})
+}
+
+// RevokeCertificate revokes the given PEM encoded certificate
+func (k *PKI) RevokeCertificate(certificate string) (*PKIRevokeResponse, error) {
+	return k.revoke("revoke", PKIRevokeRequest{Certificate: certificate})
+}
+
+// RevokeWithKey revokes a certificate proving possession of its private key, which doesn't
+// require permissions on the revoke endpoint
+func (k *PKI) RevokeWithKey(pkiopts PKIRevokeWithKeyRequest) (*PKIRevokeResponse, error) {
+	return k.revoke("revoke-with-key", pkiopts)
+}
+
+func (k *PKI) revoke(endpoint string, pkiopts interface{}) (*PKIRevokeResponse, error) {
+	response := &PKIRevokeResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			endpoint,
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIReadCRLResponse struct {
+	Data struct {
+		// Certificate holds the PEM encoded CRL of the default issuer
+		Certificate string `json:"certificate"`
+		// CRL holds the PEM encoded CRL when it was read for a specific issuer
+		CRL string `json:"crl"`
+	} `json:"data"`
+}
+
+// PEM returns the PEM encoded CRL
+func (r *PKIReadCRLResponse) PEM() string {
+	if r.Data.CRL != "" {
+		return r.Data.CRL
+	}
+
+	return r.Data.Certificate
+}
+
+// RevocationList parses the returned CRL
+func (r *PKIReadCRLResponse) RevocationList() (*x509.RevocationList, error) {
+	return ParseCRL(r.PEM())
+}
+
+// ReadCRL reads the complete CRL of the given issuer, or of the default issuer if issuerRef is empty
+func (k *PKI) ReadCRL(issuerRef string) (*PKIReadCRLResponse, error) {
+	path := []string{"v1", k.MountPoint}
+
+	if issuerRef == "" {
+		path = append(path, "cert", "crl")
+	} else {
+		path = append(path, "issuer", issuerRef, "crl")
+	}
+
+	return k.readCRL(path)
+}
+
+// ReadDeltaCRL reads the delta CRL of the given issuer, or of the default issuer if issuerRef is empty.
+// Delta CRLs have to be enabled using the CRL config.
+func (k *PKI) ReadDeltaCRL(issuerRef string) (*PKIReadCRLResponse, error) {
+	path := []string{"v1", k.MountPoint}
+
+	if issuerRef == "" {
+		path = append(path, "cert", "delta-crl")
+	} else {
+		path = append(path, "issuer", issuerRef, "crl", "delta")
+	}
+
+	return k.readCRL(path)
+}
+
+func (k *PKI) readCRL(path []string) (*PKIReadCRLResponse, error) {
+	response := &PKIReadCRLResponse{}
+	err := k.client.Read(path, response, nil)
+	if err != nil {
+		return nil, k.mapError(err)
+	}
+
+	return response, nil
+}
+
+type PKIRotateCRLResponse struct {
+	Data struct {
+		Success bool `json:"success"`
+	} `json:"data"`
+}
+
+// RotateCRL forces a rebuild of the CRLs of all issuers
+func (k *PKI) RotateCRL() (*PKIRotateCRLResponse, error) {
+	return k.rotateCRL("rotate")
+}
+
+// RotateDeltaCRL forces a rebuild of the delta CRLs of all issuers
+func (k *PKI) RotateDeltaCRL() (*PKIRotateCRLResponse, error) {
+	return k.rotateCRL("rotate-delta")
+}
+
+func (k *PKI) rotateCRL(endpoint string) (*PKIRotateCRLResponse, error) {
+	response := &PKIRotateCRLResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"crl",
+			endpoint,
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKICRLConfigRequest struct {
+	Expiry                 string `json:"expiry,omitempty"`
+	Disable                *bool  `json:"disable,omitempty"`
+	OCSPDisable            *bool  `json:"ocsp_disable,omitempty"`
+	OCSPExpiry             string `json:"ocsp_expiry,omitempty"`
+	AutoRebuild            *bool  `json:"auto_rebuild,omitempty"`
+	AutoRebuildGracePeriod string `json:"auto_rebuild_grace_period,omitempty"`
+	EnableDelta            *bool  `json:"enable_delta,omitempty"`
+	DeltaRebuildInterval   string `json:"delta_rebuild_interval,omitempty"`
+}
+
+type PKICRLConfigResponse struct {
+	Data struct {
+		Expiry                 string `json:"expiry"`
+		Disable                bool   `json:"disable"`
+		OCSPDisable            bool   `json:"ocsp_disable"`
+		OCSPExpiry             string `json:"ocsp_expiry"`
+		AutoRebuild            bool   `json:"auto_rebuild"`
+		AutoRebuildGracePeriod string `json:"auto_rebuild_grace_period"`
+		EnableDelta            bool   `json:"enable_delta"`
+		DeltaRebuildInterval   string `json:"delta_rebuild_interval"`
+	} `json:"data"`
+}
+
+func (k *PKI) ReadCRLConfig() (*PKICRLConfigResponse, error) {
+	response := &PKICRLConfigResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"crl",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) UpdateCRLConfig(pkiopts PKICRLConfigRequest) error {
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"crl",
+		}, pkiopts, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseCRL parses a PEM encoded CRL
+func ParseCRL(crlPEM string) (*x509.RevocationList, error) {
+	block, _ := pem.Decode([]byte(crlPEM))
+	if block == nil || block.Type != "X509 CRL" {
+		return nil, errors.New("no PEM encoded CRL found")
+	}
+
+	return x509.ParseRevocationList(block.Bytes)
+}
+
+// IsRevoked returns true if crl contains the given serial number
+func IsRevoked(crl *x509.RevocationList, serialNumber *big.Int) bool {
+	//nolint:staticcheck
+	for _, revoked := range crl.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(serialNumber) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKICRLTestSuite struct {
+	suite.Suite
+}
+
+func TestPKICRLTestSuite(t *testing.T) {
+	suite.Run(t, new(PKICRLTestSuite))
+}
+
+func (s *PKICRLTestSuite) TestParseCRL() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(s.T(), err)
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	require.NoError(s.T(), err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(s.T(), err)
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+		//nolint:staticcheck
+		RevokedCertificates: []pkix.RevokedCertificate{
+			{SerialNumber: big.NewInt(42), RevocationTime: time.Now()},
+		},
+	}, ca, key)
+	require.NoError(s.T(), err)
+
+	res := &PKIReadCRLResponse{}
+	res.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlDER}))
+
+	crl, err := res.RevocationList()
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), crl.CheckSignatureFrom(ca))
+
+	s.True(IsRevoked(crl, big.NewInt(42)))
+	s.False(IsRevoked(crl, big.NewInt(43)))
+}
+
+func (s *PKICRLTestSuite) TestParseCRLInvalid() {
+	_, err := ParseCRL("invalid")
+	s.Error(err)
+}
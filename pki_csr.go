@@ -0,0 +1,124 @@
+package vault
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type PKILocalKeyOptions struct {
+	// KeyType is one of "rsa" (default), "ec" or "ed25519"
+	KeyType string
+	// KeyBits defaults to 2048 for "rsa" and 256 for "ec" keys
+	KeyBits int
+
+	Subject        pkix.Name
+	DNSNames       []string
+	IPAddresses    []net.IP
+	URIs           []*url.URL
+	EmailAddresses []string
+
+	TTL string
+	// IssuerRef selects the issuer, the issuer configured in the role is used if empty
+	IssuerRef string
+}
+
+// IssueWithLocalKey generates a private key locally, lets vault sign a CSR for it using the given role and
+// returns the resulting certificate. In contrast to Issue the private key never leaves this process.
+func (k *PKI) IssueWithLocalKey(role string, pkiopts PKILocalKeyOptions) (*tls.Certificate, error) {
+	key, err := GeneratePrivateKey(pkiopts.KeyType, pkiopts.KeyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := CreateCSR(key, &x509.CertificateRequest{
+		Subject:        pkiopts.Subject,
+		DNSNames:       pkiopts.DNSNames,
+		IPAddresses:    pkiopts.IPAddresses,
+		URIs:           pkiopts.URIs,
+		EmailAddresses: pkiopts.EmailAddresses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := k.SignCSR(role, pkiopts.IssuerRef, PKISignCSROptions{
+		CSR:        csr,
+		CommonName: pkiopts.Subject.CommonName,
+		TTL:        pkiopts.TTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.TLSCertificate(key)
+}
+
+// TLSCertificate builds a tls.Certificate from the signed certificate, the CA chain and the private key used for the CSR
+func (r *PKISignResponse) TLSCertificate(key crypto.Signer) (*tls.Certificate, error) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return buildTLSCertificate(r.Data.Certificate, r.Data.CAChain, string(keyPEM))
+}
+
+// GeneratePrivateKey generates a "rsa" (default), "ec" or "ed25519" key. Bits are ignored for ed25519 keys
+// and default to 2048 for RSA and 256 for EC keys.
+func GeneratePrivateKey(keyType string, bits int) (crypto.Signer, error) {
+	switch keyType {
+	case "", "rsa":
+		if bits == 0 {
+			bits = 2048
+		}
+
+		return rsa.GenerateKey(rand.Reader, bits)
+	case "ec":
+		var curve elliptic.Curve
+
+		switch bits {
+		case 224:
+			curve = elliptic.P224()
+		case 0, 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported ec key bits %d", bits)
+		}
+
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// CreateCSR creates a PEM encoded certificate signing request for key
+func CreateCSR(key crypto.Signer, template *x509.CertificateRequest) (string, error) {
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create certificate request")
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), nil
+}
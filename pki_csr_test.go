@@ -0,0 +1,62 @@
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKICSRTestSuite struct {
+	suite.Suite
+}
+
+func TestPKICSRTestSuite(t *testing.T) {
+	suite.Run(t, new(PKICSRTestSuite))
+}
+
+func (s *PKICSRTestSuite) TestGeneratePrivateKey() {
+	key, err := GeneratePrivateKey("", 0)
+	require.NoError(s.T(), err)
+	s.Equal(2048, key.(*rsa.PrivateKey).N.BitLen())
+
+	key, err = GeneratePrivateKey("ec", 384)
+	require.NoError(s.T(), err)
+	s.Equal(384, key.(*ecdsa.PrivateKey).Curve.Params().BitSize)
+
+	key, err = GeneratePrivateKey("ed25519", 0)
+	require.NoError(s.T(), err)
+	s.IsType(ed25519.PrivateKey{}, key)
+
+	_, err = GeneratePrivateKey("ec", 123)
+	s.Error(err)
+
+	_, err = GeneratePrivateKey("dsa", 0)
+	s.Error(err)
+}
+
+func (s *PKICSRTestSuite) TestCreateCSR() {
+	key, err := GeneratePrivateKey("ec", 0)
+	require.NoError(s.T(), err)
+
+	csrPEM, err := CreateCSR(key, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "csr.example.com"},
+		DNSNames: []string{"csr.example.com", "www.csr.example.com"},
+	})
+	require.NoError(s.T(), err)
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	require.NotNil(s.T(), block)
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), csr.CheckSignature())
+	s.Equal("csr.example.com", csr.Subject.CommonName)
+	s.Equal([]string{"csr.example.com", "www.csr.example.com"}, csr.DNSNames)
+}
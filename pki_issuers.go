@@ -0,0 +1,342 @@
+package vault
+
+type PKIGenerateRootOptions struct {
+	CommonName          string   `json:"common_name"`
+	AltNames            string   `json:"alt_names,omitempty"`
+	IPSans              string   `json:"ip_sans,omitempty"`
+	URISans             string   `json:"uri_sans,omitempty"`
+	OtherSans           string   `json:"other_sans,omitempty"`
+	TTL                 string   `json:"ttl,omitempty"`
+	NotAfter            string   `json:"not_after,omitempty"`
+	NotBeforeDuration   string   `json:"not_before_duration,omitempty"`
+	Format              string   `json:"format,omitempty"`
+	PrivateKeyFormat    string   `json:"private_key_format,omitempty"`
+	KeyType             string   `json:"key_type,omitempty"`
+	KeyBits             int      `json:"key_bits,omitempty"`
+	MaxPathLength       *int     `json:"max_path_length,omitempty"`
+	ExcludeCNFromSans   bool     `json:"exclude_cn_from_sans,omitempty"`
+	PermittedDNSDomains []string `json:"permitted_dns_domains,omitempty"`
+	OU                  []string `json:"ou,omitempty"`
+	Organization        []string `json:"organization,omitempty"`
+	Country             []string `json:"country,omitempty"`
+	Locality            []string `json:"locality,omitempty"`
+	Province            []string `json:"province,omitempty"`
+	StreetAddress       []string `json:"street_address,omitempty"`
+	PostalCode          []string `json:"postal_code,omitempty"`
+	SerialNumber        string   `json:"serial_number,omitempty"`
+	IssuerName          string   `json:"issuer_name,omitempty"`
+	KeyName             string   `json:"key_name,omitempty"`
+	// KeyRef references an existing key, only used by the "existing" type
+	KeyRef string `json:"key_ref,omitempty"`
+	// ManagedKeyName and ManagedKeyID reference a managed key, only used by the "kms" type
+	ManagedKeyName string `json:"managed_key_name,omitempty"`
+	ManagedKeyID   string `json:"managed_key_id,omitempty"`
+}
+
+type PKIGenerateRootResponse struct {
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int    `json:"lease_duration"`
+	Data          struct {
+		Certificate    string `json:"certificate"`
+		Expiration     int    `json:"expiration"`
+		IssuingCA      string `json:"issuing_ca"`
+		SerialNumber   string `json:"serial_number"`
+		IssuerID       string `json:"issuer_id"`
+		IssuerName     string `json:"issuer_name"`
+		KeyID          string `json:"key_id"`
+		KeyName        string `json:"key_name"`
+		PrivateKey     string `json:"private_key"`
+		PrivateKeyType string `json:"private_key_type"`
+	} `json:"data"`
+}
+
+// GenerateRoot generates a new self-signed root CA. rootType is one of "internal", "exported", "existing" or "kms".
+func (k *PKI) GenerateRoot(rootType string, pkiopts PKIGenerateRootOptions) (*PKIGenerateRootResponse, error) {
+	return k.generateRoot("generate", rootType, pkiopts)
+}
+
+// RotateRoot generates a new root CA next to the existing one without making it the default issuer,
+// use ReplaceRoot to switch to it. rootType is one of "internal", "exported", "existing" or "kms".
+func (k *PKI) RotateRoot(rootType string, pkiopts PKIGenerateRootOptions) (*PKIGenerateRootResponse, error) {
+	return k.generateRoot("rotate", rootType, pkiopts)
+}
+
+func (k *PKI) generateRoot(operation string, rootType string, pkiopts PKIGenerateRootOptions) (*PKIGenerateRootResponse, error) {
+	response := &PKIGenerateRootResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"root",
+			operation,
+			rootType,
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIReplaceRootRequest struct {
+	Default string `json:"default,omitempty"`
+}
+
+// ReplaceRoot makes the given issuer the default issuer, the issuer named "next" is used if issuerRef is empty
+func (k *PKI) ReplaceRoot(issuerRef string) (*PKIIssuersConfigResponse, error) {
+	response := &PKIIssuersConfigResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"root",
+			"replace",
+		}, PKIReplaceRootRequest{Default: issuerRef}, response, nil,
+	)
+	if err != nil {
+		return nil, k.mapError(err)
+	}
+
+	return response, nil
+}
+
+type PKIIssuersConfigRequest struct {
+	Default                    string `json:"default,omitempty"`
+	DefaultFollowsLatestIssuer *bool  `json:"default_follows_latest_issuer,omitempty"`
+}
+
+type PKIIssuersConfigResponse struct {
+	Data struct {
+		Default                    string `json:"default"`
+		DefaultFollowsLatestIssuer bool   `json:"default_follows_latest_issuer"`
+	} `json:"data"`
+}
+
+func (k *PKI) ReadIssuersConfig() (*PKIIssuersConfigResponse, error) {
+	response := &PKIIssuersConfigResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"issuers",
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) UpdateIssuersConfig(pkiopts PKIIssuersConfigRequest) (*PKIIssuersConfigResponse, error) {
+	response := &PKIIssuersConfigResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"issuers",
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, k.mapError(err)
+	}
+
+	return response, nil
+}
+
+func (k *PKI) DeleteIssuer(issuerRef string) error {
+	err := k.client.Delete(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"issuer",
+			issuerRef,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return k.mapError(err)
+	}
+
+	return nil
+}
+
+type PKISignSelfIssuedRequest struct {
+	Certificate                          string `json:"certificate"`
+	RequireMatchingCertificateAlgorithms *bool  `json:"require_matching_certificate_algorithms,omitempty"`
+}
+
+type PKISignSelfIssuedResponse struct {
+	Data struct {
+		Certificate string `json:"certificate"`
+		IssuingCA   string `json:"issuing_ca"`
+	} `json:"data"`
+}
+
+// SignSelfIssued signs a self-issued certificate (e.g. of another root CA) using the given issuer
+func (k *PKI) SignSelfIssued(issuerRef string, pkiopts PKISignSelfIssuedRequest) (*PKISignSelfIssuedResponse, error) {
+	response := &PKISignSelfIssuedResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"issuer",
+			issuerRef,
+			"sign-self-issued",
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, k.mapError(err)
+	}
+
+	return response, nil
+}
+
+type PKIGenerateKeyOptions struct {
+	KeyName        string `json:"key_name,omitempty"`
+	KeyType        string `json:"key_type,omitempty"`
+	KeyBits        int    `json:"key_bits,omitempty"`
+	ManagedKeyName string `json:"managed_key_name,omitempty"`
+	ManagedKeyID   string `json:"managed_key_id,omitempty"`
+}
+
+type PKIKeyResponse struct {
+	Data struct {
+		KeyID        string `json:"key_id"`
+		KeyName      string `json:"key_name"`
+		KeyType      string `json:"key_type"`
+		SubjectKeyID string `json:"subject_key_id"`
+		ManagedKeyID string `json:"managed_key_id"`
+		// PrivateKey is only returned by GenerateKey using the "exported" type
+		PrivateKey string `json:"private_key"`
+	} `json:"data"`
+}
+
+// GenerateKey generates a new issuer key. keyType is one of "internal", "exported" or "kms".
+func (k *PKI) GenerateKey(keyType string, pkiopts PKIGenerateKeyOptions) (*PKIKeyResponse, error) {
+	response := &PKIKeyResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"keys",
+			"generate",
+			keyType,
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIImportKeyRequest struct {
+	PemBundle string `json:"pem_bundle"`
+	KeyName   string `json:"key_name,omitempty"`
+}
+
+func (k *PKI) ImportKey(pkiopts PKIImportKeyRequest) (*PKIKeyResponse, error) {
+	response := &PKIKeyResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"keys",
+			"import",
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type PKIListKeysResponse struct {
+	Data struct {
+		KeyInfo map[string]struct {
+			KeyName string `json:"key_name"`
+		} `json:"key_info"`
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+func (k *PKI) ListKeys() (*PKIListKeysResponse, error) {
+	response := &PKIListKeysResponse{}
+	err := k.client.List(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"keys",
+		}, nil, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *PKI) ReadKey(keyRef string) (*PKIKeyResponse, error) {
+	response := &PKIKeyResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"key",
+			keyRef,
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, k.mapError(err)
+	}
+
+	return response, nil
+}
+
+// ReadKeyOrNil is like ReadKey, but returns nil if the key doesn't exist
+func (k *PKI) ReadKeyOrNil(keyRef string) (*PKIKeyResponse, error) {
+	return orNil(k.ReadKey(keyRef))
+}
+
+type PKIUpdateKeyRequest struct {
+	KeyName string `json:"key_name"`
+}
+
+func (k *PKI) UpdateKey(keyRef string, pkiopts PKIUpdateKeyRequest) (*PKIKeyResponse, error) {
+	response := &PKIKeyResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"key",
+			keyRef,
+		}, pkiopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteKey deletes an issuer key, keys still used by an issuer can't be deleted
+func (k *PKI) DeleteKey(keyRef string) error {
+	err := k.client.Delete(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"key",
+			keyRef,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package vault
+
+import (
+	"crypto/x509"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+type OCSPStatus string
+
+const (
+	OCSPStatusGood    OCSPStatus = "good"
+	OCSPStatusRevoked OCSPStatus = "revoked"
+	OCSPStatusUnknown OCSPStatus = "unknown"
+)
+
+type PKIOCSPResponse struct {
+	Status       OCSPStatus
+	SerialNumber *big.Int
+	// RevokedAt and RevocationReason are only set if Status is OCSPStatusRevoked
+	RevokedAt        time.Time
+	RevocationReason int
+	ThisUpdate       time.Time
+	NextUpdate       time.Time
+
+	// Raw is the parsed and verified OCSP response
+	Raw *ocsp.Response
+}
+
+// OCSPStatus queries the OCSP responder of the mount for the status of a PEM encoded certificate.
+// issuerChain starts with the issuing CA, e.g. PKIIssueResponse.Data.CAChain or PKIReadIssuerResponse.Data.CACertificateChain.
+// The chain and the response signature are verified before the status is returned.
+func (k *PKI) OCSPStatus(certificate string, issuerChain []string) (*PKIOCSPResponse, error) {
+	cert, err := ParseCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := parseCertificateChain(issuerChain)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyCertificateChain(cert, chain); err != nil {
+		return nil, err
+	}
+
+	issuer := chain[0]
+
+	ocspReq, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ocsp request")
+	}
+
+	raw, err := k.ocspRequest(ocspReq)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ocsp.ParseResponseForCert(raw, cert, issuer)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ocsp response")
+	}
+
+	response := &PKIOCSPResponse{
+		SerialNumber: res.SerialNumber,
+		ThisUpdate:   res.ThisUpdate,
+		NextUpdate:   res.NextUpdate,
+		Raw:          res,
+	}
+
+	switch res.Status {
+	case ocsp.Good:
+		response.Status = OCSPStatusGood
+	case ocsp.Revoked:
+		response.Status = OCSPStatusRevoked
+		response.RevokedAt = res.RevokedAt
+		response.RevocationReason = res.RevocationReason
+	default:
+		response.Status = OCSPStatusUnknown
+	}
+
+	return response, nil
+}
+
+func (k *PKI) ocspRequest(body []byte) ([]byte, error) {
+	var raw []byte
+
+	// OCSP requests only query the status, so they can safely be retried
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"ocsp",
+		}, &RawBody{ContentType: "application/ocsp-request", Data: body}, &raw, &RequestOptions{Idempotent: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// verifyCertificateChain checks that cert is signed by chain[0] and each element of chain by its successor
+func verifyCertificateChain(cert *x509.Certificate, chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("empty issuer chain")
+	}
+
+	if err := cert.CheckSignatureFrom(chain[0]); err != nil {
+		return errors.Wrap(err, "certificate is not signed by the issuer")
+	}
+
+	for i := 1; i < len(chain); i++ {
+		if err := chain[i-1].CheckSignatureFrom(chain[i]); err != nil {
+			return errors.Wrapf(err, "invalid issuer chain %q", chain[i-1].Subject.CommonName)
+		}
+	}
+
+	return nil
+}
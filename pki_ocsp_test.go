@@ -0,0 +1,146 @@
+package vault
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  string
+}
+
+// newTestCertificate creates a certificate signed by parent, or a self-signed one if parent is nil
+func newTestCertificate(t *testing.T, commonName string, isCA bool, notAfter time.Time, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+
+	signerCert, signerKey := tmpl, crypto.Signer(key)
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert: cert,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+type PKIOCSPTestSuite struct {
+	suite.Suite
+}
+
+func TestPKIOCSPTestSuite(t *testing.T) {
+	suite.Run(t, new(PKIOCSPTestSuite))
+}
+
+func (s *PKIOCSPTestSuite) TestVerifyCertificateChain() {
+	root := newTestCertificate(s.T(), "root", true, time.Now().Add(time.Hour), nil)
+	intermediate := newTestCertificate(s.T(), "intermediate", true, time.Now().Add(time.Hour), root)
+	leaf := newTestCertificate(s.T(), "leaf", false, time.Now().Add(time.Hour), intermediate)
+	other := newTestCertificate(s.T(), "other", true, time.Now().Add(time.Hour), nil)
+
+	s.NoError(verifyCertificateChain(leaf.cert, []*x509.Certificate{intermediate.cert, root.cert}))
+	s.Error(verifyCertificateChain(leaf.cert, []*x509.Certificate{root.cert}))
+	s.Error(verifyCertificateChain(leaf.cert, []*x509.Certificate{intermediate.cert, other.cert}))
+	s.Error(verifyCertificateChain(leaf.cert, nil))
+}
+
+func (s *PKIOCSPTestSuite) TestOCSPStatus() {
+	ca := newTestCertificate(s.T(), "ca", true, time.Now().Add(time.Hour), nil)
+	leaf := newTestCertificate(s.T(), "leaf", false, time.Now().Add(time.Hour), ca)
+	revokedAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/v1/pki/ocsp", r.URL.Path)
+		s.Equal("application/ocsp-request", r.Header.Get("Content-Type"))
+		s.Equal("tenant", r.Header.Get("X-Vault-Namespace"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(s.T(), err)
+
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(s.T(), err)
+
+		res, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:           ocsp.Revoked,
+			SerialNumber:     req.SerialNumber,
+			ThisUpdate:       time.Now(),
+			RevokedAt:        revokedAt,
+			RevocationReason: ocsp.KeyCompromise,
+		}, ca.key)
+		require.NoError(s.T(), err)
+
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(res)
+	}))
+	defer server.Close()
+
+	root, err := NewClient(server.URL, nil)
+	require.NoError(s.T(), err)
+	client := root.InNamespace("tenant")
+
+	res, err := client.PKI().OCSPStatus(leaf.pem, []string{ca.pem})
+	require.NoError(s.T(), err)
+	s.Equal(OCSPStatusRevoked, res.Status)
+	s.Equal(leaf.cert.SerialNumber, res.SerialNumber)
+	s.Equal(revokedAt, res.RevokedAt)
+	s.Equal(ocsp.KeyCompromise, res.RevocationReason)
+
+	other := newTestCertificate(s.T(), "other", true, time.Now().Add(time.Hour), nil)
+	_, err = client.PKI().OCSPStatus(leaf.pem, []string{other.pem})
+	s.Error(err)
+}
+
+func (s *PKIOCSPTestSuite) TestOCSPStatusError() {
+	ca := newTestCertificate(s.T(), "ca", true, time.Now().Add(time.Hour), nil)
+	leaf := newTestCertificate(s.T(), "leaf", false, time.Now().Add(time.Hour), ca)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil)
+	require.NoError(s.T(), err)
+
+	// errors are returned like the ones of all other requests
+	_, err = client.PKI().OCSPStatus(leaf.pem, []string{ca.pem})
+	s.True(IsNotFound(err))
+}
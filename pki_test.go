@@ -0,0 +1,328 @@
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"fmt"
+	"testing"
+
+	"github.com/mittwald/vaultgo/test/testdata"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKITestSuite struct {
+	suite.Suite
+	client  *PKI
+	version string
+}
+
+func TestPKITestSuite(t *testing.T) {
+	for _, v := range testdata.VaultVersions {
+		require.NoError(t, testdata.Init(context.Background(), v))
+
+		t.Logf("using vault uri %v", testdata.Vault.URI())
+		client, _ := NewClient(testdata.Vault.URI(), WithCaPath(""))
+		client.SetToken(testdata.Vault.Token())
+
+		pkiTestSuite := new(PKITestSuite)
+		pkiTestSuite.client = client.PKI()
+		pkiTestSuite.version = v
+
+		suite.Run(t, pkiTestSuite)
+	}
+}
+
+// skipBefore skips tests for APIs which were added in the given vault minor version
+func (s *PKITestSuite) skipBefore(minor int) {
+	var major, current int
+	_, err := fmt.Sscanf(s.version, "%d.%d", &major, &current)
+	require.NoError(s.T(), err)
+
+	if major == 1 && current < minor {
+		s.T().Skipf("not supported by vault %s", s.version)
+	}
+}
+
+func (s *PKITestSuite) TestIssueReadAndRevoke() {
+	issued, err := s.client.Issue("test", PKIIssueOptions{CommonName: "issue-read-revoke.example.com"})
+	require.NoError(s.T(), err)
+
+	certs, err := s.client.ListCertificates()
+	require.NoError(s.T(), err)
+	s.Contains(certs.Data.Keys, issued.Data.SerialNumber)
+
+	cert, err := s.client.ReadCertificate(issued.Data.SerialNumber)
+	require.NoError(s.T(), err)
+	s.Equal("issue-read-revoke.example.com", cert.ParsedCertificate.Subject.CommonName)
+	s.Zero(cert.Data.RevocationTime)
+
+	_, err = s.client.Revoke(issued.Data.SerialNumber)
+	require.NoError(s.T(), err)
+
+	crlRes, err := s.client.ReadCRL("")
+	require.NoError(s.T(), err)
+	crl, err := crlRes.RevocationList()
+	require.NoError(s.T(), err)
+	s.True(IsRevoked(crl, cert.ParsedCertificate.SerialNumber))
+
+	s.skipBefore(12)
+
+	revoked, err := s.client.ListRevoked()
+	require.NoError(s.T(), err)
+	s.Contains(revoked.Data.Keys, issued.Data.SerialNumber)
+}
+
+func (s *PKITestSuite) TestTidy() {
+	s.skipBefore(12)
+
+	err := s.client.Tidy(PKITidyRequest{
+		TidyCertStore:    BoolPtr(true),
+		TidyRevokedCerts: BoolPtr(true),
+		SafetyBuffer:     "1h",
+	})
+	require.NoError(s.T(), err)
+
+	status, err := s.client.TidyStatus()
+	require.NoError(s.T(), err)
+	s.NotEmpty(status.Data.State)
+}
+
+func (s *PKITestSuite) TestCertManager() {
+	rotated := 0
+	m, err := NewPKICertManager(s.client, "test", PKIIssueOptions{CommonName: "cert-manager.example.com", TTL: "1h"},
+		WithPKIRotationHook(func(cert *tls.Certificate) { rotated++ }),
+	)
+	require.NoError(s.T(), err)
+
+	first, err := m.GetCertificate(nil)
+	require.NoError(s.T(), err)
+	s.Equal("cert-manager.example.com", first.Leaf.Subject.CommonName)
+
+	require.NoError(s.T(), m.Renew())
+
+	second, err := m.GetClientCertificate(nil)
+	require.NoError(s.T(), err)
+	s.NotEqual(first.Leaf.SerialNumber, second.Leaf.SerialNumber)
+	s.Equal(2, rotated)
+}
+
+func (s *PKITestSuite) TestIssueWithLocalKey() {
+	// the test role only accepts rsa keys
+	cert, err := s.client.IssueWithLocalKey("test", PKILocalKeyOptions{
+		KeyType:  "rsa",
+		Subject:  pkix.Name{CommonName: "local-key.example.com"},
+		DNSNames: []string{"local-key.example.com"},
+		TTL:      "1h",
+	})
+	require.NoError(s.T(), err)
+
+	s.Equal("local-key.example.com", cert.Leaf.Subject.CommonName)
+	s.Equal(cert.PrivateKey.(crypto.Signer).Public(), cert.Leaf.PublicKey)
+}
+
+func (s *PKITestSuite) TestIssueWithLocalECKey() {
+	_, err := s.client.CreateOrUpdateRole("local-ec", PKICreateRoleRequest{
+		AllowAnyName: BoolPtr(true),
+		MaxTTL:       "72h",
+		KeyType:      "ec",
+	})
+	require.NoError(s.T(), err)
+
+	cert, err := s.client.IssueWithLocalKey("local-ec", PKILocalKeyOptions{
+		KeyType:  "ec",
+		Subject:  pkix.Name{CommonName: "local-ec-key.example.com"},
+		DNSNames: []string{"local-ec-key.example.com"},
+		TTL:      "1h",
+	})
+	require.NoError(s.T(), err)
+
+	s.Equal("local-ec-key.example.com", cert.Leaf.Subject.CommonName)
+	s.Equal(cert.PrivateKey.(crypto.Signer).Public(), cert.Leaf.PublicKey)
+}
+
+func (s *PKITestSuite) TestKeys() {
+	s.skipBefore(12)
+
+	key, err := s.client.GenerateKey("internal", PKIGenerateKeyOptions{KeyName: "test-key", KeyType: "ec"})
+	require.NoError(s.T(), err)
+	s.Equal("ec", key.Data.KeyType)
+
+	keys, err := s.client.ListKeys()
+	require.NoError(s.T(), err)
+	s.Contains(keys.Data.Keys, key.Data.KeyID)
+
+	_, err = s.client.UpdateKey(key.Data.KeyID, PKIUpdateKeyRequest{KeyName: "test-key-renamed"})
+	require.NoError(s.T(), err)
+
+	read, err := s.client.ReadKey("test-key-renamed")
+	require.NoError(s.T(), err)
+	s.Equal(key.Data.KeyID, read.Data.KeyID)
+
+	require.NoError(s.T(), s.client.DeleteKey(key.Data.KeyID))
+}
+
+func (s *PKITestSuite) TestRotateRoot() {
+	s.skipBefore(12)
+
+	root, err := s.client.RotateRoot("internal", PKIGenerateRootOptions{
+		CommonName: "vaultgo rotated root",
+		IssuerName: "rotated",
+		TTL:        "24h",
+	})
+	require.NoError(s.T(), err)
+
+	issuer, err := s.client.ReadIssuer("rotated")
+	require.NoError(s.T(), err)
+	s.Equal(root.Data.IssuerID, issuer.Data.IssuerID)
+
+	config, err := s.client.ReadIssuersConfig()
+	require.NoError(s.T(), err)
+	s.NotEqual(root.Data.IssuerID, config.Data.Default)
+
+	require.NoError(s.T(), s.client.DeleteIssuer("rotated"))
+}
+
+func (s *PKITestSuite) TestRoleRoundTrip() {
+	_, err := s.client.CreateOrUpdateRole("roundtrip", PKICreateRoleRequest{
+		TTL:                           "1h",
+		MaxTTL:                        "24h",
+		AllowedDomains:                []string{"example.com"},
+		AllowBareDomain:               true,
+		AllowSubdomains:               true,
+		AllowLocalhost:                BoolPtr(false),
+		EnforceHostnames:              BoolPtr(false),
+		AllowIPSans:                   BoolPtr(false),
+		AllowedURISans:                []string{"spiffe://example.com/*"},
+		ServerFlag:                    true,
+		ClientFlag:                    BoolPtr(false),
+		KeyType:                       "ec",
+		KeyBits:                       384,
+		KeyUsage:                      []string{"DigitalSignature"},
+		ExtKeyUsage:                   []string{"ServerAuth"},
+		OU:                            []string{"ops"},
+		Organization:                  []string{"vaultgo"},
+		Country:                       []string{"DE"},
+		NoStore:                       BoolPtr(true),
+		PolicyIdentifiers:             []string{"1.2.3.4"},
+		UseCSRCommonName:              BoolPtr(false),
+		BasicConstraintsValidForNonCA: BoolPtr(true),
+	})
+	require.NoError(s.T(), err)
+
+	role, err := s.client.ReadRole("roundtrip")
+	require.NoError(s.T(), err)
+
+	s.Equal(3600, role.Data.TTL)
+	s.Equal(86400, role.Data.MaxTTL)
+	s.Equal([]string{"example.com"}, role.Data.AllowedDomains)
+	s.True(role.Data.AllowBareDomains)
+	s.True(role.Data.AllowSubdomains)
+	s.False(role.Data.AllowLocalhost)
+	s.False(role.Data.EnforceHostnames)
+	s.False(role.Data.AllowIPSans)
+	s.Equal([]string{"spiffe://example.com/*"}, role.Data.AllowedURISans)
+	s.True(role.Data.ServerFlag)
+	s.False(role.Data.ClientFlag)
+	s.Equal("ec", role.Data.KeyType)
+	s.Equal(384, role.Data.KeyBits)
+	s.Equal([]string{"DigitalSignature"}, role.Data.KeyUsage)
+	s.Equal([]string{"ServerAuth"}, role.Data.ExtKeyUsage)
+	s.Equal([]string{"ops"}, role.Data.OU)
+	s.Equal([]string{"vaultgo"}, role.Data.Organization)
+	s.Equal([]string{"DE"}, role.Data.Country)
+	s.True(role.Data.NoStore)
+	s.Equal([]string{"1.2.3.4"}, role.Data.PolicyIdentifiers)
+	s.False(role.Data.UseCSRCommonName)
+	s.True(role.Data.BasicConstraintsValidForNonCA)
+
+	roles, err := s.client.ListRoles()
+	require.NoError(s.T(), err)
+	s.Contains(roles.Data.Keys, "roundtrip")
+
+	require.NoError(s.T(), s.client.DeleteRole("roundtrip"))
+
+	roles, err = s.client.ListRoles()
+	require.NoError(s.T(), err)
+	s.NotContains(roles.Data.Keys, "roundtrip")
+}
+
+func (s *PKITestSuite) TestOCSPStatus() {
+	s.skipBefore(12)
+
+	issued, err := s.client.Issue("test", PKIIssueOptions{CommonName: "ocsp.example.com"})
+	require.NoError(s.T(), err)
+
+	status, err := s.client.OCSPStatus(issued.Data.Certificate, issued.Data.CAChain)
+	require.NoError(s.T(), err)
+	s.Equal(OCSPStatusGood, status.Status)
+
+	_, err = s.client.Revoke(issued.Data.SerialNumber)
+	require.NoError(s.T(), err)
+
+	status, err = s.client.OCSPStatus(issued.Data.Certificate, issued.Data.CAChain)
+	require.NoError(s.T(), err)
+	s.Equal(OCSPStatusRevoked, status.Status)
+	s.False(status.RevokedAt.IsZero())
+}
+
+func (s *PKITestSuite) TestURLsConfig() {
+	err := s.client.UpdateURLsConfig(PKIURLsConfigRequest{
+		IssuingCertificates:   []string{"http://vault:8200/v1/pki/ca"},
+		CRLDistributionPoints: []string{"http://vault:8200/v1/pki/crl"},
+	})
+	require.NoError(s.T(), err)
+
+	config, err := s.client.ReadURLsConfig()
+	require.NoError(s.T(), err)
+	s.Equal([]string{"http://vault:8200/v1/pki/ca"}, config.Data.IssuingCertificates)
+	s.Equal([]string{"http://vault:8200/v1/pki/crl"}, config.Data.CRLDistributionPoints)
+}
+
+func (s *PKITestSuite) TestCRLConfig() {
+	err := s.client.UpdateCRLConfig(PKICRLConfigRequest{Expiry: "48h"})
+	require.NoError(s.T(), err)
+
+	config, err := s.client.ReadCRLConfig()
+	require.NoError(s.T(), err)
+	s.Equal("48h", config.Data.Expiry)
+}
+
+func (s *PKITestSuite) TestReadNotFound() {
+	_, err := s.client.ReadRole("not-found")
+	s.ErrorIs(err, ErrNotFound)
+
+	role, err := s.client.ReadRoleOrNil("not-found")
+	require.NoError(s.T(), err)
+	s.Nil(role)
+
+	role, err = s.client.ReadRoleOrNil("test")
+	require.NoError(s.T(), err)
+	s.NotNil(role)
+
+	_, err = s.client.ReadCertificate("01:02:03:04")
+	s.ErrorIs(err, ErrNotFound)
+
+	cert, err := s.client.ReadCertificateOrNil("01:02:03:04")
+	require.NoError(s.T(), err)
+	s.Nil(cert)
+
+	s.skipBefore(12)
+
+	_, err = s.client.ReadIssuer("not-found")
+	s.ErrorIs(err, ErrIssuerNotFound)
+	s.ErrorIs(err, ErrNotFound)
+
+	issuer, err := s.client.ReadIssuerOrNil("not-found")
+	require.NoError(s.T(), err)
+	s.Nil(issuer)
+
+	_, err = s.client.ReadKey("not-found")
+	s.ErrorIs(err, ErrNotFound)
+
+	key, err := s.client.ReadKeyOrNil("not-found")
+	require.NoError(s.T(), err)
+	s.Nil(key)
+}
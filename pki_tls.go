@@ -0,0 +1,167 @@
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TLSCertificate builds a tls.Certificate from the issued certificate, its private key and the CA chain
+func (r *PKIIssueResponse) TLSCertificate() (*tls.Certificate, error) {
+	return buildTLSCertificate(r.Data.Certificate, r.Data.CAChain, r.Data.PrivateKey)
+}
+
+func buildTLSCertificate(certificate string, caChain []string, privateKey string) (*tls.Certificate, error) {
+	chain := append([]string{certificate}, caChain...)
+
+	cert, err := tls.X509KeyPair([]byte(strings.Join(chain, "\n")), []byte(privateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build tls certificate")
+	}
+
+	cert.Leaf, err = ParseCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// PKICertManager issues a certificate using a PKI role and renews it in the background
+// before it expires. It can be used as certificate source of a tls.Config.
+type PKICertManager struct {
+	certRenewer
+
+	pki     *PKI
+	role    string
+	pkiopts PKIIssueOptions
+
+	onRotate func(cert *tls.Certificate)
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+type PKICertManagerOpt func(m *PKICertManager) error
+
+// WithPKIRenewFraction sets the fraction of the certificate lifetime after which it is renewed, defaults to 2/3
+func WithPKIRenewFraction(fraction float64) PKICertManagerOpt {
+	return func(m *PKICertManager) error {
+		return m.setRenewFraction(fraction)
+	}
+}
+
+// WithPKIRetryInterval sets the bounds of the exponential backoff used if a renewal fails,
+// defaults to 5 seconds and 5 minutes
+func WithPKIRetryInterval(minInterval time.Duration, maxInterval time.Duration) PKICertManagerOpt {
+	return func(m *PKICertManager) error {
+		return m.setRetryInterval(minInterval, maxInterval)
+	}
+}
+
+// WithPKIRotationHook registers a function called with the initial certificate and after each successful renewal
+func WithPKIRotationHook(hook func(cert *tls.Certificate)) PKICertManagerOpt {
+	return func(m *PKICertManager) error {
+		m.onRotate = hook
+
+		return nil
+	}
+}
+
+// WithPKIRenewErrorHook registers a function called each time a renewal fails
+func WithPKIRenewErrorHook(hook func(err error)) PKICertManagerOpt {
+	return func(m *PKICertManager) error {
+		m.onError = hook
+
+		return nil
+	}
+}
+
+// NewPKICertManager issues the initial certificate, Run has to be called to keep it renewed
+func NewPKICertManager(pki *PKI, role string, pkiopts PKIIssueOptions, opts ...PKICertManagerOpt) (*PKICertManager, error) {
+	m := &PKICertManager{
+		pki:     pki,
+		role:    role,
+		pkiopts: pkiopts,
+	}
+
+	m.init(m.issue, func() {
+		if m.onRotate != nil {
+			m.onRotate(m.Certificate())
+		}
+	})
+
+	for _, opt := range opts {
+		err := opt(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.Renew(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Run renews the certificate until ctx is canceled
+func (m *PKICertManager) Run(ctx context.Context) error {
+	return m.run(ctx)
+}
+
+// Renew issues a new certificate and replaces the current one
+func (m *PKICertManager) Renew() error {
+	return m.renew()
+}
+
+func (m *PKICertManager) issue() error {
+	res, err := m.pki.Issue(m.role, m.pkiopts)
+	if err != nil {
+		return err
+	}
+
+	return m.setCertificate(res)
+}
+
+func (m *PKICertManager) setCertificate(res *PKIIssueResponse) error {
+	cert, err := res.TLSCertificate()
+	if err != nil {
+		return err
+	}
+
+	notAfter := cert.Leaf.NotAfter
+	if res.Data.Expiration > 0 {
+		notAfter = time.Unix(int64(res.Data.Expiration), 0)
+	}
+
+	m.mu.Lock()
+	m.cert = cert
+	m.mu.Unlock()
+
+	m.setValidity(cert.Leaf.NotBefore, notAfter)
+
+	return nil
+}
+
+// Certificate returns the current certificate
+func (m *PKICertManager) Certificate() *tls.Certificate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.cert
+}
+
+// GetCertificate can be used as tls.Config.GetCertificate
+func (m *PKICertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return m.Certificate(), nil
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate
+func (m *PKICertManager) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return m.Certificate(), nil
+}
@@ -0,0 +1,83 @@
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PKITLSTestSuite struct {
+	suite.Suite
+}
+
+func TestPKITLSTestSuite(t *testing.T) {
+	suite.Run(t, new(PKITLSTestSuite))
+}
+
+// issueTestCertificate returns a self-signed certificate valid from notBefore to notAfter as issue response
+func issueTestCertificate(t *testing.T, notBefore time.Time, notAfter time.Time) *PKIIssueResponse {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	res := &PKIIssueResponse{}
+	res.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	res.Data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	res.Data.Expiration = int(notAfter.Unix())
+
+	return res
+}
+
+func (s *PKITLSTestSuite) TestTLSCertificate() {
+	res := issueTestCertificate(s.T(), time.Now(), time.Now().Add(time.Hour))
+
+	cert, err := res.TLSCertificate()
+	require.NoError(s.T(), err)
+	s.Len(cert.Certificate, 1)
+	s.Equal("test", cert.Leaf.Subject.CommonName)
+}
+
+func (s *PKITLSTestSuite) TestRenewIn() {
+	now := time.Now().Truncate(time.Second)
+	m := &PKICertManager{certRenewer: certRenewer{renewFraction: 0.5, now: func() time.Time { return now }}}
+
+	require.NoError(s.T(), m.setCertificate(issueTestCertificate(s.T(), now, now.Add(4*time.Hour))))
+	s.Equal(2*time.Hour, m.renewIn())
+
+	cert, err := m.GetCertificate(nil)
+	require.NoError(s.T(), err)
+	s.Same(m.Certificate(), cert)
+
+	require.NoError(s.T(), m.setCertificate(issueTestCertificate(s.T(), now.Add(-4*time.Hour), now.Add(time.Hour))))
+	s.Equal(time.Duration(0), m.renewIn())
+}
+
+func (s *PKITLSTestSuite) TestRetryIn() {
+	m := &PKICertManager{certRenewer: certRenewer{minRetryInterval: time.Second, maxRetryInterval: 10 * time.Second}}
+
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 5: 10 * time.Second, 100: 10 * time.Second} {
+		wait := m.retryIn(attempt)
+		s.LessOrEqual(wait, expected)
+		s.GreaterOrEqual(wait, expected*4/5)
+	}
+}
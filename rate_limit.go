@@ -0,0 +1,114 @@
+package vault
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit limits the requests sent by a Client, see WithRateLimit
+type RateLimit struct {
+	// PathPrefix restricts the limit to requests whose path starts with it (e.g. "/v1/transit/"),
+	// all requests are limited if it is empty
+	PathPrefix string
+	// RequestsPerSecond is the rate of the token bucket, requests aren't rate limited if it is 0
+	RequestsPerSecond float64
+	// Burst is the size of the token bucket, defaults to 1
+	Burst int
+	// MaxInFlight limits the number of concurrent requests, they aren't limited if it is 0
+	MaxInFlight int
+}
+
+type requestLimiter struct {
+	prefix   string
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newRequestLimiter(limit RateLimit) (*requestLimiter, error) {
+	if limit.RequestsPerSecond < 0 || limit.Burst < 0 || limit.MaxInFlight < 0 {
+		return nil, errors.New("rate limits must not be negative")
+	}
+
+	l := &requestLimiter{
+		prefix: limit.PathPrefix,
+	}
+
+	if l.prefix != "" && !strings.HasPrefix(l.prefix, "/") {
+		l.prefix = "/" + l.prefix
+	}
+
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = 1
+		}
+
+		l.limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	return l, nil
+}
+
+func (l *requestLimiter) matches(path string) bool {
+	return strings.HasPrefix(path, l.prefix)
+}
+
+// acquireLimits blocks until all limits matching path allow another request or the context of the client
+// is canceled, the returned function has to be called once the request finished. Tokens are taken before
+// any in-flight slot is held, so waiting for a token never blocks other requests.
+func (c *Client) acquireLimits(path string) (func(), error) {
+	var matching []*requestLimiter
+
+	for _, l := range c.limiters {
+		if l.matches(path) {
+			matching = append(matching, l)
+		}
+	}
+
+	ctx := c.context()
+
+	for _, l := range matching {
+		if l.limiter != nil {
+			if err := l.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// slots of more specific limits are acquired first, so requests waiting for them don't hold slots of
+	// broader limits. The order is the same for all requests, which prevents deadlocks between them.
+	sort.SliceStable(matching, func(i, j int) bool {
+		return len(matching[i].prefix) > len(matching[j].prefix)
+	})
+
+	var acquired []*requestLimiter
+
+	release := func() {
+		for _, l := range acquired {
+			<-l.inFlight
+		}
+	}
+
+	for _, l := range matching {
+		if l.inFlight == nil {
+			continue
+		}
+
+		select {
+		case l.inFlight <- struct{}{}:
+			acquired = append(acquired, l)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
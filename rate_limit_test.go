@@ -0,0 +1,124 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newConcurrencyServer returns a server responding after delay, which records the maximum number
+// of concurrent requests per first path element below /v1
+func newConcurrencyServer(delay time.Duration) (*httptest.Server, func(mount string) int32) {
+	var mu sync.Mutex
+	current := map[string]*int32{}
+	maximum := map[string]*int32{}
+
+	counters := func(mount string) (*int32, *int32) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if current[mount] == nil {
+			current[mount], maximum[mount] = new(int32), new(int32)
+		}
+
+		return current[mount], maximum[mount]
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mount := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")[0]
+		cur, max := counters(mount)
+
+		n := atomic.AddInt32(cur, 1)
+		for {
+			m := atomic.LoadInt32(max)
+			if n <= m || atomic.CompareAndSwapInt32(max, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(delay)
+		atomic.AddInt32(cur, -1)
+
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+
+	return server, func(mount string) int32 {
+		_, max := counters(mount)
+		return atomic.LoadInt32(max)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server, _ := newConcurrencyServer(0)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"), WithRateLimit(RateLimit{RequestsPerSecond: 50}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		require.NoError(t, client.Read([]string{"v1", "kv", "key"}, nil, nil))
+	}
+
+	// the first request passes immediately, the others have to wait 20ms each
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestMaxInFlight(t *testing.T) {
+	server, maxConcurrent := newConcurrencyServer(20 * time.Millisecond)
+	defer server.Close()
+
+	client, err := NewClient(
+		server.URL, nil,
+		WithAuthToken("token"),
+		WithRateLimit(
+			RateLimit{MaxInFlight: 4},
+			RateLimit{PathPrefix: "/v1/transit/", MaxInFlight: 1},
+		),
+	)
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		for _, mount := range []string{"transit", "kv"} {
+			wg.Add(1)
+			go func(mount string) {
+				defer wg.Done()
+				require.NoError(t, client.Read([]string{"v1", mount, "key"}, nil, nil))
+			}(mount)
+		}
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), maxConcurrent("transit"))
+	require.LessOrEqual(t, maxConcurrent("kv"), int32(4))
+	require.Greater(t, maxConcurrent("kv"), int32(1))
+}
+
+func TestRateLimitValidation(t *testing.T) {
+	_, err := NewClient("http://127.0.0.1:8200", nil, WithRateLimit(RateLimit{MaxInFlight: -1}))
+	require.Error(t, err)
+}
+
+func TestRateLimitCanceled(t *testing.T) {
+	server, _ := newConcurrencyServer(0)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"), WithRateLimit(RateLimit{RequestsPerSecond: 0.001}))
+	require.NoError(t, err)
+	require.NoError(t, client.Read([]string{"v1", "kv", "key"}, nil, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// the next token is only available in 1000s
+	err = client.WithContext(ctx).Read([]string{"v1", "kv", "key"}, nil, nil)
+	require.Error(t, err)
+}
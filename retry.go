@@ -0,0 +1,144 @@
+package vault
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryAttempt describes a failed request attempt passed to the RetryPolicy
+type RetryAttempt struct {
+	// Attempt is the number of the failed attempt, starting at 1
+	Attempt int
+	Method  string
+	Path    string
+	// Idempotent is set for requests which can safely be sent multiple times,
+	// see RequestOptions.Idempotent
+	Idempotent bool
+	Err        error
+}
+
+// RetryPolicy decides if and when a failed request is retried
+type RetryPolicy interface {
+	// NextRetry returns whether the failed attempt should be retried and how long to wait before
+	NextRetry(attempt RetryAttempt) (time.Duration, bool)
+}
+
+// BackoffRetryPolicy retries retryable errors (see IsRetryable) with exponential backoff and jitter
+type BackoffRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, defaults to 4
+	MaxAttempts int
+	// MinBackoff is the wait after the first failed attempt, it is doubled for each further attempt.
+	// Defaults to 250ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, defaults to 10s
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the wait which is randomly subtracted, defaults to 0.2.
+	// A negative value disables jitter.
+	Jitter float64
+	// Retryable overrides the classification of errors, IsRetryable is used if nil
+	Retryable func(attempt RetryAttempt) bool
+}
+
+func DefaultRetryPolicy() *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+func (p *BackoffRetryPolicy) NextRetry(attempt RetryAttempt) (time.Duration, bool) {
+	defaults := DefaultRetryPolicy()
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaults.MaxAttempts
+	}
+
+	if attempt.Attempt >= maxAttempts {
+		return 0, false
+	}
+
+	if p.Retryable != nil {
+		if !p.Retryable(attempt) {
+			return 0, false
+		}
+	} else if !IsRetryable(attempt.Err, attempt.Idempotent) {
+		return 0, false
+	}
+
+	minBackoff, maxBackoff, jitter := p.MinBackoff, p.MaxBackoff, p.Jitter
+	if minBackoff <= 0 {
+		minBackoff = defaults.MinBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = defaults.MaxBackoff
+	}
+	if jitter == 0 || jitter > 1 {
+		jitter = defaults.Jitter
+	} else if jitter < 0 {
+		jitter = 0
+	}
+
+	wait := maxBackoff
+	if attempt.Attempt < 32 {
+		if backoff := minBackoff << (attempt.Attempt - 1); backoff > 0 && backoff < wait {
+			wait = backoff
+		}
+	}
+
+	//nolint:gosec // jitter doesn't need a secure random source
+	return wait - time.Duration(rand.Int63n(int64(float64(wait)*jitter)+1)), true
+}
+
+// IsRetryable reports whether a request failing with err may succeed if retried.
+// Rate limited, sealed and standby responses, 412 responses of performance standbys, refused connections
+// and ErrUnavailable are always retryable, as vault didn't process the request. Other server errors, connection resets and
+// timeouts are only retryable for idempotent requests, as vault might have processed the request already.
+func IsRetryable(err error, idempotent bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsRateLimited(err) || IsSealed(err) || IsStandby(err) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, ErrUnavailable) {
+		return true
+	}
+
+	vaultErr := &Error{}
+	if errors.As(err, &vaultErr) && vaultErr.StatusCode != 0 {
+		if vaultErr.StatusCode == http.StatusPreconditionFailed {
+			return true
+		}
+
+		return idempotent && vaultErr.StatusCode >= http.StatusInternalServerError && vaultErr.StatusCode != http.StatusNotImplemented
+	}
+
+	if !idempotent {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isIdempotent reports whether requests using method can be sent multiple times without further effects.
+// Writes are not, as e.g. issuing certificates or generating credentials creates new objects each time.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions, "LIST":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,250 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetryTestSuite struct {
+	suite.Suite
+	failures int
+	status   int
+	message  string
+	requests int
+	retries  []RetryAttempt
+	client   *Client
+	server   *httptest.Server
+}
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryTestSuite))
+}
+
+// SetupTest starts a server failing the first s.failures requests with s.status
+func (s *RetryTestSuite) SetupTest() {
+	s.failures = 0
+	s.status = http.StatusInternalServerError
+	s.message = "internal error"
+	s.requests = 0
+	s.retries = nil
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+
+		if s.requests <= s.failures {
+			w.WriteHeader(s.status)
+			_, _ = w.Write([]byte(`{"errors":["` + s.message + `"]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+
+	var err error
+	s.client, err = NewClient(
+		s.server.URL, nil,
+		WithAuthToken("token"),
+		WithRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}),
+		WithRetryHook(func(attempt RetryAttempt, wait time.Duration) {
+			s.retries = append(s.retries, attempt)
+		}),
+	)
+	require.NoError(s.T(), err)
+}
+
+func (s *RetryTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *RetryTestSuite) TestRetryIdempotentRequests() {
+	s.failures = 2
+
+	require.NoError(s.T(), s.client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	s.Equal(3, s.requests)
+	s.Len(s.retries, 2)
+	s.Equal(1, s.retries[0].Attempt)
+	s.Equal("GET", s.retries[0].Method)
+	s.Equal("/v1/kv/key", s.retries[0].Path)
+	s.True(s.retries[0].Idempotent)
+	s.Equal(http.StatusInternalServerError, s.retries[0].Err.(*Error).StatusCode)
+}
+
+func (s *RetryTestSuite) TestMaxAttempts() {
+	s.failures = 3
+
+	err := s.client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil)
+	s.Error(err)
+	s.Equal(3, s.requests)
+	s.Len(s.retries, 2)
+}
+
+func (s *RetryTestSuite) TestNonIdempotentWrites() {
+	s.failures = 1
+
+	// vault might have processed the write
+	s.Error(s.client.Write([]string{"v1", "pki", "issue", "role"}, nil, &struct{}{}, nil))
+	s.Equal(1, s.requests)
+
+	s.requests = 0
+	require.NoError(s.T(), s.client.Write([]string{"v1", "kv", "key"}, nil, &struct{}{}, &RequestOptions{Idempotent: true}))
+	s.Equal(2, s.requests)
+
+	// rate limited requests weren't processed
+	s.requests = 0
+	s.status = http.StatusTooManyRequests
+	s.message = "rate limit quota exceeded"
+	require.NoError(s.T(), s.client.Write([]string{"v1", "pki", "issue", "role"}, nil, &struct{}{}, nil))
+	s.Equal(2, s.requests)
+}
+
+func (s *RetryTestSuite) TestNotRetryable() {
+	s.failures = 1
+	s.status = http.StatusBadRequest
+
+	s.Error(s.client.Read([]string{"v1", "kv", "key"}, &struct{}{}, nil))
+	s.Equal(1, s.requests)
+	s.Empty(s.retries)
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err        error
+		idempotent bool
+		retryable  bool
+	}{
+		{err: &Error{StatusCode: http.StatusInternalServerError}, idempotent: true, retryable: true},
+		{err: &Error{StatusCode: http.StatusInternalServerError}, idempotent: false, retryable: false},
+		{err: &Error{StatusCode: http.StatusNotImplemented}, idempotent: true, retryable: false},
+		{err: &Error{StatusCode: http.StatusBadGateway}, idempotent: true, retryable: true},
+		{err: &Error{StatusCode: http.StatusTooManyRequests}, idempotent: false, retryable: true},
+		{err: &Error{StatusCode: http.StatusPreconditionFailed}, idempotent: false, retryable: true},
+		{err: &Error{StatusCode: http.StatusServiceUnavailable, Errors: []string{"Vault is sealed"}}, idempotent: false, retryable: true},
+		{err: &Error{StatusCode: http.StatusInternalServerError, Errors: []string{"local node not active but active cluster node not found"}}, idempotent: false, retryable: true},
+		{err: &Error{StatusCode: http.StatusNotFound}, idempotent: true, retryable: false},
+		{err: &Error{StatusCode: http.StatusForbidden}, idempotent: true, retryable: false},
+		{err: &Error{Err: errors.New("unsupported protocol scheme")}, idempotent: true, retryable: false},
+		{err: nil, idempotent: true, retryable: false},
+	} {
+		require.Equal(t, tc.retryable, IsRetryable(tc.err, tc.idempotent), "%v idempotent=%v", tc.err, tc.idempotent)
+	}
+}
+
+func TestRetryConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	addr := server.URL
+	server.Close()
+
+	attempts := 0
+	client, err := NewClient(
+		addr, nil,
+		WithAuthToken("token"),
+		WithRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}),
+		WithRetryHook(func(attempt RetryAttempt, wait time.Duration) { attempts++ }),
+	)
+	require.NoError(t, err)
+
+	// refused connections are retried for writes, vault didn't receive the request
+	require.Error(t, client.Write([]string{"v1", "pki", "issue", "role"}, nil, nil, nil))
+	require.Equal(t, 1, attempts)
+}
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	policy := &BackoffRetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.5}
+	err := &Error{StatusCode: http.StatusTooManyRequests}
+
+	for attempt, expected := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		wait, retry := policy.NextRetry(RetryAttempt{Attempt: attempt, Err: err})
+		require.True(t, retry)
+		require.LessOrEqual(t, wait, expected)
+		require.GreaterOrEqual(t, wait, expected/2)
+	}
+
+	_, retry := policy.NextRetry(RetryAttempt{Attempt: 10, Err: err})
+	require.False(t, retry)
+
+	// defaults apply to zero values
+	wait, retry := (&BackoffRetryPolicy{}).NextRetry(RetryAttempt{Attempt: 1, Err: err})
+	require.True(t, retry)
+	require.LessOrEqual(t, wait, 250*time.Millisecond)
+
+	policy.Retryable = func(attempt RetryAttempt) bool { return false }
+	_, retry = policy.NextRetry(RetryAttempt{Attempt: 1, Err: err})
+	require.False(t, retry)
+}
+
+func (s *RetryTestSuite) TestCancelRetry() {
+	s.failures = 10
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client, err := NewClient(s.server.URL, nil, WithAuthToken("token"), WithRetryPolicy(&BackoffRetryPolicy{MinBackoff: time.Minute}))
+	require.NoError(s.T(), err)
+
+	start := time.Now()
+	err = client.WithContext(ctx).Read([]string{"v1", "kv", "key"}, &struct{}{}, nil)
+	s.Less(time.Since(start), time.Second)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Equal(1, s.requests)
+}
+
+func TestBackoffRetryPolicyWithoutJitter(t *testing.T) {
+	policy := &BackoffRetryPolicy{MinBackoff: 100 * time.Millisecond, Jitter: -1}
+
+	for attempt, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond} {
+		wait, retry := policy.NextRetry(RetryAttempt{Attempt: attempt, Err: &Error{StatusCode: http.StatusTooManyRequests}})
+		require.True(t, retry)
+		require.Equal(t, expected, wait)
+	}
+}
+
+type countingAuth struct {
+	calls int
+}
+
+func (a *countingAuth) Auth() (*AuthResponse, error) {
+	a.calls++
+
+	res := &AuthResponse{}
+	res.Auth.ClientToken = "renewed"
+
+	return res, nil
+}
+
+func TestRenewalNotSkippedForReusedOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	auth := &countingAuth{}
+	client.auth = auth
+
+	opts := &RequestOptions{}
+	for i := 0; i < 2; i++ {
+		require.Error(t, client.Read([]string{"v1", "kv", "key"}, &struct{}{}, opts))
+	}
+
+	// the token is renewed once per request, the options of the caller aren't modified
+	require.Equal(t, 2, auth.calls)
+	require.False(t, opts.SkipRenewal)
+}
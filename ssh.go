@@ -1,5 +1,11 @@
 package vault
 
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
 type SSH struct {
 	Service
 }
@@ -17,10 +23,33 @@ func (c *Client) SSHWithMountPoint(mountPoint string) *SSH {
 	}
 }
 
+const (
+	SSHCertTypeUser = "user"
+	SSHCertTypeHost = "host"
+)
+
 type SSHSignOptions struct {
-	PublicKey       string `json:"public_key"`
-	CertType        string `json:"cert_type,omitempty"`
-	ValidPrincipals string `json:"valid_principals,omitempty"`
+	PublicKey string `json:"public_key"`
+	// CertType is either SSHCertTypeUser (default) or SSHCertTypeHost
+	CertType        string            `json:"cert_type,omitempty"`
+	ValidPrincipals []string          `json:"-"`
+	TTL             string            `json:"ttl,omitempty"`
+	KeyID           string            `json:"key_id,omitempty"`
+	CriticalOptions map[string]string `json:"critical_options,omitempty"`
+	Extensions      map[string]string `json:"extensions,omitempty"`
+}
+
+// MarshalJSON sends ValidPrincipals as the comma separated list expected by vault
+func (o SSHSignOptions) MarshalJSON() ([]byte, error) {
+	type signOptions SSHSignOptions
+
+	return json.Marshal(struct {
+		signOptions
+		ValidPrincipals string `json:"valid_principals,omitempty"`
+	}{
+		signOptions:     signOptions(o),
+		ValidPrincipals: strings.Join(o.ValidPrincipals, ","),
+	})
 }
 
 type SSHSignResponse struct {
@@ -59,6 +88,13 @@ func (k *SSH) Sign(role string, sshopts SSHSignOptions) (*SSHSignResponse, error
 	return response, nil
 }
 
+// SignHostKey signs a host key, sshopts.ValidPrincipals should contain the host names of the host
+func (k *SSH) SignHostKey(role string, sshopts SSHSignOptions) (*SSHSignResponse, error) {
+	sshopts.CertType = SSHCertTypeHost
+
+	return k.Sign(role, sshopts)
+}
+
 func (k *SSH) GetVaultPubKey() (string, error) {
 	response := &SSHReadPubKeyResponse{}
 	err := k.client.Read(
@@ -70,8 +106,84 @@ func (k *SSH) GetVaultPubKey() (string, error) {
 		}, response, nil,
 	)
 	if err != nil {
-		return "", err
+		return "", k.mapError(err)
 	}
 
 	return response.Data.PublicKey, nil
 }
+
+type SSHConfigCARequest struct {
+	// PrivateKey and PublicKey import an existing CA key pair, GenerateSigningKey has to be false then
+	PrivateKey         string `json:"private_key,omitempty"`
+	PublicKey          string `json:"public_key,omitempty"`
+	GenerateSigningKey *bool  `json:"generate_signing_key,omitempty"`
+	// KeyType and KeyBits are used when generating a key, they are supported since vault 1.9
+	KeyType string `json:"key_type,omitempty"`
+	KeyBits int    `json:"key_bits,omitempty"`
+}
+
+type SSHConfigCAResponse struct {
+	Data struct {
+		PublicKey string `json:"public_key"`
+	} `json:"data"`
+}
+
+// GenerateCA generates a new CA key pair. It fails if a CA is configured already, use DeleteCA first to replace it.
+func (k *SSH) GenerateCA(sshopts SSHConfigCARequest) (*SSHConfigCAResponse, error) {
+	sshopts.PrivateKey = ""
+	sshopts.PublicKey = ""
+	sshopts.GenerateSigningKey = BoolPtr(true)
+
+	return k.configureCA(sshopts)
+}
+
+// ImportCA configures an existing key pair as CA. It fails if a CA is configured already, use DeleteCA first to replace it.
+func (k *SSH) ImportCA(privateKey string, publicKey string) (*SSHConfigCAResponse, error) {
+	return k.configureCA(SSHConfigCARequest{
+		PrivateKey:         privateKey,
+		PublicKey:          publicKey,
+		GenerateSigningKey: BoolPtr(false),
+	})
+}
+
+func (k *SSH) configureCA(sshopts SSHConfigCARequest) (*SSHConfigCAResponse, error) {
+	response := &SSHConfigCAResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"ca",
+		}, sshopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *SSH) DeleteCA() error {
+	err := k.client.Delete(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"config",
+			"ca",
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (k *SSH) mapError(err error) error {
+	vaultErr := &Error{}
+	if errors.As(err, &vaultErr) && vaultErr.hasMessage("keys haven't been configured yet") {
+		return vaultErr.withErr(ErrSSHCANotConfigured)
+	}
+
+	return err
+}
@@ -0,0 +1,232 @@
+package vault
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHCertAuthority is the CA public key of a SSH mount
+type SSHCertAuthority struct {
+	MountPoint string
+	Key        ssh.PublicKey
+}
+
+// sshManagedComment prefixes the comment of generated lines, only these lines are replaced when writing a file
+const sshManagedComment = "vault:"
+
+// line returns the key in the authorized_keys format, commented with the mount point
+func (a SSHCertAuthority) line() string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(a.Key))) + " " + sshManagedComment + a.MountPoint
+}
+
+// isManagedSSHLine reports whether line was generated, i.e. its comment starts with "vault:"
+func isManagedSSHLine(line string) bool {
+	fields := strings.Fields(line)
+
+	return len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], sshManagedComment)
+}
+
+// SSHCertAuthorities reads the CA public keys of the given mounts, CAs shared by multiple mounts are returned once
+func SSHCertAuthorities(mounts ...*SSH) ([]SSHCertAuthority, error) {
+	var cas []SSHCertAuthority
+
+	seen := make(map[string]bool)
+
+	for _, mount := range mounts {
+		caKey, err := mount.GetVaultPubKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA of mount %s", mount.MountPoint)
+		}
+
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse CA of mount %s", mount.MountPoint)
+		}
+
+		if seen[string(key.Marshal())] {
+			continue
+		}
+		seen[string(key.Marshal())] = true
+
+		cas = append(cas, SSHCertAuthority{MountPoint: mount.MountPoint, Key: key})
+	}
+
+	return cas, nil
+}
+
+// SSHKnownHosts returns known_hosts content trusting host certificates signed by the given CAs
+// for hosts matching one of hostPatterns (e.g. "*.example.com"), all hosts are matched if hostPatterns is empty
+func SSHKnownHosts(hostPatterns []string, cas []SSHCertAuthority) []byte {
+	hosts := "*"
+	if len(hostPatterns) > 0 {
+		hosts = strings.Join(hostPatterns, ",")
+	}
+
+	buf := &bytes.Buffer{}
+	for _, ca := range cas {
+		buf.WriteString("@cert-authority " + hosts + " " + ca.line() + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+// SSHTrustedUserCAKeys returns the content of a file referenced by the TrustedUserCAKeys option of sshd
+func SSHTrustedUserCAKeys(cas []SSHCertAuthority) []byte {
+	buf := &bytes.Buffer{}
+	for _, ca := range cas {
+		buf.WriteString(ca.line() + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+type SSHFileOptions struct {
+	// Perm is the mode of the written file, defaults to 0644
+	Perm os.FileMode
+	// DiffOnly only computes the difference to the existing file, nothing is written
+	DiffOnly bool
+}
+
+// SSHFileDiff contains the lines added to and removed from a file
+type SSHFileDiff struct {
+	Added   []string
+	Removed []string
+}
+
+func (d *SSHFileDiff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// WriteSSHKnownHosts writes a known_hosts file trusting the CAs of the given mounts, see SSHKnownHosts.
+// Only lines generated by this package (commented with "vault:<mount>") are replaced, all other lines
+// of an existing file are kept.
+func WriteSSHKnownHosts(path string, hostPatterns []string, sshopts SSHFileOptions, mounts ...*SSH) (*SSHFileDiff, error) {
+	cas, err := SSHCertAuthorities(mounts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeSSHFile(path, SSHKnownHosts(hostPatterns, cas), sshopts)
+}
+
+// WriteSSHTrustedUserCAKeys writes a TrustedUserCAKeys file trusting the CAs of the given mounts.
+// Only lines generated by this package (commented with "vault:<mount>") are replaced, all other lines
+// of an existing file are kept.
+func WriteSSHTrustedUserCAKeys(path string, sshopts SSHFileOptions, mounts ...*SSH) (*SSHFileDiff, error) {
+	cas, err := SSHCertAuthorities(mounts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeSSHFile(path, SSHTrustedUserCAKeys(cas), sshopts)
+}
+
+func writeSSHFile(path string, content []byte, sshopts SSHFileOptions) (*SSHFileDiff, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	merged, managed := mergeSSHFile(current, content)
+	diff := diffLines(managed, content)
+
+	if sshopts.DiffOnly {
+		return diff, nil
+	}
+
+	perm := sshopts.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+
+	if _, err := writeFileAtomic(path, merged, perm); err != nil {
+		return nil, errors.Wrapf(err, "failed to write %s", path)
+	}
+
+	return diff, nil
+}
+
+// mergeSSHFile replaces the generated lines of current by generated and returns the result and the replaced lines.
+// The generated lines are inserted where the first generated line was found, or appended.
+func mergeSSHFile(current []byte, generated []byte) ([]byte, []byte) {
+	var kept []string
+
+	managed := &bytes.Buffer{}
+	insertAt := -1
+
+	lines := strings.Split(string(current), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, line := range lines {
+		if isManagedSSHLine(line) {
+			if insertAt < 0 {
+				insertAt = len(kept)
+			}
+
+			managed.WriteString(line + "\n")
+
+			continue
+		}
+
+		kept = append(kept, line)
+	}
+
+	if insertAt < 0 {
+		insertAt = len(kept)
+	}
+
+	merged := &bytes.Buffer{}
+	for i, line := range kept {
+		if i == insertAt {
+			merged.Write(generated)
+		}
+
+		merged.WriteString(line + "\n")
+	}
+
+	if insertAt == len(kept) {
+		merged.Write(generated)
+	}
+
+	return merged.Bytes(), managed.Bytes()
+}
+
+// diffLines returns the non-empty lines of b missing in a as added and those of a missing in b as removed
+func diffLines(a []byte, b []byte) *SSHFileDiff {
+	diff := &SSHFileDiff{}
+
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+
+	for _, line := range linesB {
+		if !containsString(linesA, line) {
+			diff.Added = append(diff.Added, line)
+		}
+	}
+
+	for _, line := range linesA {
+		if !containsString(linesB, line) {
+			diff.Removed = append(diff.Removed, line)
+		}
+	}
+
+	return diff
+}
+
+func splitLines(content []byte) []string {
+	var lines []string
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,149 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHFilesTestSuite struct {
+	suite.Suite
+	userCA ssh.Signer
+	hostCA ssh.Signer
+	client *Client
+	server *httptest.Server
+}
+
+func TestSSHFilesTestSuite(t *testing.T) {
+	suite.Run(t, new(SSHFilesTestSuite))
+}
+
+func (s *SSHFilesTestSuite) SetupTest() {
+	s.userCA = newTestSSHSigner(s.T())
+	s.hostCA = newTestSSHSigner(s.T())
+
+	cas := map[string]ssh.Signer{
+		"/v1/ssh-user/config/ca":      s.userCA,
+		"/v1/ssh-user-copy/config/ca": s.userCA,
+		"/v1/ssh-host/config/ca":      s.hostCA,
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ca, ok := cas[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"public_key": string(ssh.MarshalAuthorizedKey(ca.PublicKey()))},
+		})
+	}))
+
+	var err error
+	s.client, err = NewClient(s.server.URL, nil)
+	require.NoError(s.T(), err)
+}
+
+func (s *SSHFilesTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *SSHFilesTestSuite) authorizedKey(signer ssh.Signer) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+}
+
+func (s *SSHFilesTestSuite) TestKnownHosts() {
+	cas, err := SSHCertAuthorities(s.client.SSHWithMountPoint("ssh-host"), s.client.SSHWithMountPoint("ssh-user"))
+	require.NoError(s.T(), err)
+
+	s.Equal(
+		"@cert-authority *.example.com,*.example.org "+s.authorizedKey(s.hostCA)+" vault:ssh-host\n"+
+			"@cert-authority *.example.com,*.example.org "+s.authorizedKey(s.userCA)+" vault:ssh-user\n",
+		string(SSHKnownHosts([]string{"*.example.com", "*.example.org"}, cas)),
+	)
+	s.Equal("@cert-authority * "+s.authorizedKey(s.hostCA)+" vault:ssh-host\n", string(SSHKnownHosts(nil, cas[:1])))
+
+	// the written file is accepted by the ssh package
+	path := filepath.Join(s.T().TempDir(), "known_hosts")
+	_, err = WriteSSHKnownHosts(path, []string{"*.example.com"}, SSHFileOptions{}, s.client.SSHWithMountPoint("ssh-host"))
+	require.NoError(s.T(), err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(s.T(), err)
+
+	marker, hosts, key, comment, _, err := ssh.ParseKnownHosts(content)
+	require.NoError(s.T(), err)
+	s.Equal("cert-authority", marker)
+	s.Equal([]string{"*.example.com"}, hosts)
+	s.Equal(s.hostCA.PublicKey().Marshal(), key.Marshal())
+	s.Equal("vault:ssh-host", comment)
+}
+
+func (s *SSHFilesTestSuite) TestTrustedUserCAKeys() {
+	path := filepath.Join(s.T().TempDir(), "trusted-user-ca-keys.pem")
+	userLine := s.authorizedKey(s.userCA) + " vault:ssh-user"
+	hostLine := s.authorizedKey(s.hostCA) + " vault:ssh-host"
+
+	// mounts sharing a CA are only written once
+	diff, err := WriteSSHTrustedUserCAKeys(path, SSHFileOptions{}, s.client.SSHWithMountPoint("ssh-user"), s.client.SSHWithMountPoint("ssh-user-copy"))
+	require.NoError(s.T(), err)
+	s.True(diff.Changed())
+	s.Equal([]string{userLine}, diff.Added)
+
+	content, err := os.ReadFile(path)
+	require.NoError(s.T(), err)
+	s.Equal(userLine+"\n", string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(s.T(), err)
+	s.Equal(os.FileMode(0o644), info.Mode().Perm())
+
+	diff, err = WriteSSHTrustedUserCAKeys(path, SSHFileOptions{}, s.client.SSHWithMountPoint("ssh-user"))
+	require.NoError(s.T(), err)
+	s.False(diff.Changed())
+
+	// diff only mode leaves the file untouched
+	diff, err = WriteSSHTrustedUserCAKeys(path, SSHFileOptions{DiffOnly: true}, s.client.SSHWithMountPoint("ssh-host"))
+	require.NoError(s.T(), err)
+	s.Equal([]string{hostLine}, diff.Added)
+	s.Equal([]string{userLine}, diff.Removed)
+
+	content, err = os.ReadFile(path)
+	require.NoError(s.T(), err)
+	s.Equal(userLine+"\n", string(content))
+
+	_, err = WriteSSHTrustedUserCAKeys(path, SSHFileOptions{}, s.client.SSHWithMountPoint("ssh-missing"))
+	s.Error(err)
+}
+
+func (s *SSHFilesTestSuite) TestKeepUnmanagedLines() {
+	path := filepath.Join(s.T().TempDir(), "known_hosts")
+	hostLine := "@cert-authority * " + s.authorizedKey(s.hostCA) + " vault:ssh-host"
+	userLine := "@cert-authority * " + s.authorizedKey(s.userCA) + " vault:ssh-user"
+	existing := "# managed by hand\n" +
+		"github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n" +
+		userLine + "\n" +
+		"gitlab.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf\n"
+	require.NoError(s.T(), os.WriteFile(path, []byte(existing), 0o600))
+
+	diff, err := WriteSSHKnownHosts(path, nil, SSHFileOptions{}, s.client.SSHWithMountPoint("ssh-host"))
+	require.NoError(s.T(), err)
+	s.Equal([]string{hostLine}, diff.Added)
+	s.Equal([]string{userLine}, diff.Removed)
+
+	// generated lines are replaced in place, all other lines are kept
+	content, err := os.ReadFile(path)
+	require.NoError(s.T(), err)
+	s.Equal(strings.Replace(existing, userLine, hostLine, 1), string(content))
+}
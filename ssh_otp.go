@@ -0,0 +1,67 @@
+package vault
+
+type SSHCredsOptions struct {
+	IP       string `json:"ip"`
+	Username string `json:"username,omitempty"`
+}
+
+type SSHCredsResponse struct {
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int    `json:"lease_duration"`
+	Data          struct {
+		Key      string `json:"key"`
+		KeyType  string `json:"key_type"`
+		Username string `json:"username"`
+		IP       string `json:"ip"`
+		Port     int    `json:"port"`
+	} `json:"data"`
+}
+
+// Creds generates a one-time password for logging into the host at sshopts.IP using an OTP role
+func (k *SSH) Creds(role string, sshopts SSHCredsOptions) (*SSHCredsResponse, error) {
+	response := &SSHCredsResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"creds",
+			role,
+		}, sshopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type SSHVerifyRequest struct {
+	OTP string `json:"otp"`
+}
+
+type SSHVerifyResponse struct {
+	Data struct {
+		Username string `json:"username"`
+		IP       string `json:"ip"`
+		RoleName string `json:"role_name"`
+	} `json:"data"`
+}
+
+// Verify validates and consumes a one-time password, it is used by the vault-ssh-helper on the target host.
+// An invalid or already used OTP results in an error.
+func (k *SSH) Verify(otp string) (*SSHVerifyResponse, error) {
+	response := &SSHVerifyResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"verify",
+		}, SSHVerifyRequest{OTP: otp}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,181 @@
+package vault
+
+const (
+	SSHKeyTypeOTP = "otp"
+	SSHKeyTypeCA  = "ca"
+)
+
+type SSHRoleRequest struct {
+	// KeyType is either SSHKeyTypeOTP or SSHKeyTypeCA
+	KeyType                string `json:"key_type"`
+	DefaultUser            string `json:"default_user,omitempty"`
+	DefaultUserTemplate    *bool  `json:"default_user_template,omitempty"`
+	AllowedUsers           string `json:"allowed_users,omitempty"`
+	AllowedUsersTemplate   *bool  `json:"allowed_users_template,omitempty"`
+	AllowedDomains         string `json:"allowed_domains,omitempty"`
+	AllowedDomainsTemplate *bool  `json:"allowed_domains_template,omitempty"`
+
+	// CIDRList, ExcludeCIDRList and Port are only used by OTP roles
+	CIDRList        string `json:"cidr_list,omitempty"`
+	ExcludeCIDRList string `json:"exclude_cidr_list,omitempty"`
+	Port            int    `json:"port,omitempty"`
+
+	// the following fields are only used by CA roles
+	TTL                       string            `json:"ttl,omitempty"`
+	MaxTTL                    string            `json:"max_ttl,omitempty"`
+	NotBeforeDuration         string            `json:"not_before_duration,omitempty"`
+	KeyIDFormat               string            `json:"key_id_format,omitempty"`
+	AllowedCriticalOptions    string            `json:"allowed_critical_options,omitempty"`
+	AllowedExtensions         string            `json:"allowed_extensions,omitempty"`
+	DefaultCriticalOptions    map[string]string `json:"default_critical_options,omitempty"`
+	DefaultExtensions         map[string]string `json:"default_extensions,omitempty"`
+	DefaultExtensionsTemplate *bool             `json:"default_extensions_template,omitempty"`
+	AllowUserCertificates     *bool             `json:"allow_user_certificates,omitempty"`
+	AllowHostCertificates     *bool             `json:"allow_host_certificates,omitempty"`
+	AllowBareDomains          *bool             `json:"allow_bare_domains,omitempty"`
+	AllowSubdomains           *bool             `json:"allow_subdomains,omitempty"`
+	AllowUserKeyIDs           *bool             `json:"allow_user_key_ids,omitempty"`
+	AllowedUserKeyLengths     map[string]int    `json:"allowed_user_key_lengths,omitempty"`
+	AlgorithmSigner           string            `json:"algorithm_signer,omitempty"`
+}
+
+type SSHRoleResponse struct {
+	Data struct {
+		KeyType                string `json:"key_type"`
+		DefaultUser            string `json:"default_user"`
+		DefaultUserTemplate    bool   `json:"default_user_template"`
+		AllowedUsers           string `json:"allowed_users"`
+		AllowedUsersTemplate   bool   `json:"allowed_users_template"`
+		AllowedDomains         string `json:"allowed_domains"`
+		AllowedDomainsTemplate bool   `json:"allowed_domains_template"`
+
+		CIDRList        string `json:"cidr_list"`
+		ExcludeCIDRList string `json:"exclude_cidr_list"`
+		Port            int    `json:"port"`
+
+		TTL                       int               `json:"ttl"`
+		MaxTTL                    int               `json:"max_ttl"`
+		NotBeforeDuration         int               `json:"not_before_duration"`
+		KeyIDFormat               string            `json:"key_id_format"`
+		AllowedCriticalOptions    string            `json:"allowed_critical_options"`
+		AllowedExtensions         string            `json:"allowed_extensions"`
+		DefaultCriticalOptions    map[string]string `json:"default_critical_options"`
+		DefaultExtensions         map[string]string `json:"default_extensions"`
+		DefaultExtensionsTemplate bool              `json:"default_extensions_template"`
+		AllowUserCertificates     bool              `json:"allow_user_certificates"`
+		AllowHostCertificates     bool              `json:"allow_host_certificates"`
+		AllowBareDomains          bool              `json:"allow_bare_domains"`
+		AllowSubdomains           bool              `json:"allow_subdomains"`
+		AllowUserKeyIDs           bool              `json:"allow_user_key_ids"`
+		// AllowedUserKeyLengths maps key types to a length or, since vault 1.10, a list of lengths
+		AllowedUserKeyLengths map[string]interface{} `json:"allowed_user_key_lengths"`
+		AlgorithmSigner       string                 `json:"algorithm_signer"`
+	} `json:"data"`
+}
+
+func (k *SSH) CreateOrUpdateRole(roleName string, sshopts SSHRoleRequest) error {
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+			roleName,
+		}, sshopts, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (k *SSH) ReadRole(roleName string) (*SSHRoleResponse, error) {
+	response := &SSHRoleResponse{}
+	err := k.client.Read(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+			roleName,
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ReadRoleOrNil is like ReadRole, but returns nil if the role doesn't exist
+func (k *SSH) ReadRoleOrNil(roleName string) (*SSHRoleResponse, error) {
+	return orNil(k.ReadRole(roleName))
+}
+
+type SSHListRolesResponse struct {
+	Data struct {
+		Keys    []string `json:"keys"`
+		KeyInfo map[string]struct {
+			KeyType string `json:"key_type"`
+		} `json:"key_info"`
+	} `json:"data"`
+}
+
+func (k *SSH) ListRoles() (*SSHListRolesResponse, error) {
+	response := &SSHListRolesResponse{}
+	err := k.client.List(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+		}, nil, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (k *SSH) DeleteRole(roleName string) error {
+	err := k.client.Delete(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"roles",
+			roleName,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type SSHLookupRequest struct {
+	IP       string `json:"ip"`
+	Username string `json:"username,omitempty"`
+}
+
+type SSHLookupResponse struct {
+	Data struct {
+		Roles []string `json:"roles"`
+	} `json:"data"`
+}
+
+// Lookup lists the OTP roles whose CIDR list contains the given IP (and allowing the given user, if set)
+func (k *SSH) Lookup(sshopts SSHLookupRequest) (*SSHLookupResponse, error) {
+	response := &SSHLookupResponse{}
+	err := k.client.Write(
+		[]string{
+			"v1",
+			k.MountPoint,
+			"lookup",
+		}, sshopts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,240 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// ParseSSHCertificate parses a certificate in the authorized_keys format, e.g. SSHSignResponse.Data.SignedKey
+func ParseSSHCertificate(signedKey string) (*ssh.Certificate, error) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(signedKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signed key")
+	}
+
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, errors.New("signed key is not a certificate")
+	}
+
+	return cert, nil
+}
+
+// SignKey signs the public key of signer, sshopts.PublicKey is ignored
+func (k *SSH) SignKey(role string, signer ssh.Signer, sshopts SSHSignOptions) (*ssh.Certificate, error) {
+	sshopts.PublicKey = string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+
+	res, err := k.Sign(role, sshopts)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := ParseSSHCertificate(res.Data.SignedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(cert.Key.Marshal(), signer.PublicKey().Marshal()) {
+		return nil, errors.New("signed certificate doesn't match the public key")
+	}
+
+	return cert, nil
+}
+
+// HostKeyCallback returns a callback for ssh.ClientConfig accepting host certificates signed by the CA of this mount
+func (k *SSH) HostKeyCallback() (ssh.HostKeyCallback, error) {
+	caKey, err := k.GetVaultPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ca, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CA public key")
+	}
+
+	return SSHHostKeyCallback(ca), nil
+}
+
+// SSHHostKeyCallback returns a callback accepting host certificates signed by one of the given CAs.
+// The certificate has to be valid and list the host name connected to as principal.
+func SSHHostKeyCallback(caKeys ...ssh.PublicKey) ssh.HostKeyCallback {
+	checker := &ssh.CertChecker{
+		IsHostAuthority: func(auth ssh.PublicKey, address string) bool {
+			for _, ca := range caKeys {
+				if bytes.Equal(auth.Marshal(), ca.Marshal()) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+
+	return checker.CheckHostKey
+}
+
+// SSHCertSigner is a ssh.Signer presenting a certificate signed by vault, which is renewed in the background
+// before it expires. It can be used with ssh.PublicKeys in a ssh.ClientConfig.
+type SSHCertSigner struct {
+	certRenewer
+
+	ssh     *SSH
+	role    string
+	signer  ssh.Signer
+	sshopts SSHSignOptions
+
+	onRotate func(cert *ssh.Certificate)
+
+	mu         sync.RWMutex
+	cert       *ssh.Certificate
+	certSigner ssh.Signer
+}
+
+type SSHCertSignerOpt func(s *SSHCertSigner) error
+
+// WithSSHRenewFraction sets the fraction of the certificate lifetime after which it is renewed, defaults to 2/3
+func WithSSHRenewFraction(fraction float64) SSHCertSignerOpt {
+	return func(s *SSHCertSigner) error {
+		return s.setRenewFraction(fraction)
+	}
+}
+
+// WithSSHRetryInterval sets the bounds of the exponential backoff used if a renewal fails,
+// defaults to 5 seconds and 5 minutes
+func WithSSHRetryInterval(minInterval time.Duration, maxInterval time.Duration) SSHCertSignerOpt {
+	return func(s *SSHCertSigner) error {
+		return s.setRetryInterval(minInterval, maxInterval)
+	}
+}
+
+// WithSSHRotationHook registers a function called with the initial certificate and after each successful renewal
+func WithSSHRotationHook(hook func(cert *ssh.Certificate)) SSHCertSignerOpt {
+	return func(s *SSHCertSigner) error {
+		s.onRotate = hook
+
+		return nil
+	}
+}
+
+// WithSSHRenewErrorHook registers a function called each time a renewal fails
+func WithSSHRenewErrorHook(hook func(err error)) SSHCertSignerOpt {
+	return func(s *SSHCertSigner) error {
+		s.onError = hook
+
+		return nil
+	}
+}
+
+// NewSSHCertSigner signs the public key of signer, Run has to be called to keep the certificate renewed
+func NewSSHCertSigner(sshClient *SSH, role string, signer ssh.Signer, sshopts SSHSignOptions, opts ...SSHCertSignerOpt) (*SSHCertSigner, error) {
+	s := &SSHCertSigner{
+		ssh:     sshClient,
+		role:    role,
+		signer:  signer,
+		sshopts: sshopts,
+	}
+
+	s.init(s.sign, func() {
+		if s.onRotate != nil {
+			s.onRotate(s.Certificate())
+		}
+	})
+
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Renew(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Run renews the certificate until ctx is canceled
+func (s *SSHCertSigner) Run(ctx context.Context) error {
+	return s.run(ctx)
+}
+
+// Renew signs a new certificate and replaces the current one
+func (s *SSHCertSigner) Renew() error {
+	return s.renew()
+}
+
+func (s *SSHCertSigner) sign() error {
+	cert, err := s.ssh.SignKey(s.role, s.signer, s.sshopts)
+	if err != nil {
+		return err
+	}
+
+	certSigner, err := ssh.NewCertSigner(cert, s.signer)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cert = cert
+	s.certSigner = certSigner
+	s.mu.Unlock()
+
+	// certificates valid forever are never renewed
+	var notAfter time.Time
+	if cert.ValidBefore != ssh.CertTimeInfinity {
+		notAfter = time.Unix(int64(cert.ValidBefore), 0)
+	}
+
+	s.setValidity(time.Unix(int64(cert.ValidAfter), 0), notAfter)
+
+	return nil
+}
+
+// Certificate returns the current certificate
+func (s *SSHCertSigner) Certificate() *ssh.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cert
+}
+
+// PublicKey returns the current certificate, see ssh.Signer
+func (s *SSHCertSigner) PublicKey() ssh.PublicKey {
+	return s.Certificate()
+}
+
+// Sign signs data using the underlying key, see ssh.Signer
+func (s *SSHCertSigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	s.mu.RLock()
+	certSigner := s.certSigner
+	s.mu.RUnlock()
+
+	return certSigner.Sign(rand, data)
+}
+
+// SignWithAlgorithm signs data using the given algorithm (e.g. rsa-sha2-256), see ssh.AlgorithmSigner
+func (s *SSHCertSigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (*ssh.Signature, error) {
+	s.mu.RLock()
+	certSigner := s.certSigner
+	s.mu.RUnlock()
+
+	algorithmSigner, ok := certSigner.(ssh.AlgorithmSigner)
+	if !ok {
+		return nil, errors.New("underlying signer doesn't support signature algorithms")
+	}
+
+	return algorithmSigner.SignWithAlgorithm(rand, data, algorithm)
+}
+
+// Signers can be passed to ssh.PublicKeysCallback to always authenticate with the current certificate
+func (s *SSHCertSigner) Signers() ([]ssh.Signer, error) {
+	return []ssh.Signer{s}, nil
+}
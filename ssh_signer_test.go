@@ -0,0 +1,175 @@
+package vault
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHSignerTestSuite struct {
+	suite.Suite
+	ca     ssh.Signer
+	ttl    time.Duration
+	signed int
+	ssh    *SSH
+	server *httptest.Server
+}
+
+func TestSSHSignerTestSuite(t *testing.T) {
+	suite.Run(t, new(SSHSignerTestSuite))
+}
+
+func newTestSSHSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	return signer
+}
+
+func signTestSSHCertificate(t *testing.T, ca ssh.Signer, pub ssh.PublicKey, certType uint32, principals []string, ttl time.Duration) *ssh.Certificate {
+	cert := &ssh.Certificate{
+		Key:             pub,
+		CertType:        certType,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(time.Now().Add(ttl).Unix()),
+	}
+	require.NoError(t, cert.SignCert(rand.Reader, ca))
+
+	return cert
+}
+
+func (s *SSHSignerTestSuite) SetupTest() {
+	s.ca = newTestSSHSigner(s.T())
+	s.ttl = time.Hour
+	s.signed = 0
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+
+		switch r.URL.Path {
+		case "/v1/ssh/config/ca":
+			data = map[string]string{"public_key": string(ssh.MarshalAuthorizedKey(s.ca.PublicKey()))}
+		case "/v1/ssh/sign/test":
+			req := map[string]interface{}{}
+			require.NoError(s.T(), json.NewDecoder(r.Body).Decode(&req))
+
+			pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req["public_key"].(string)))
+			require.NoError(s.T(), err)
+
+			principals := strings.Split(req["valid_principals"].(string), ",")
+			cert := signTestSSHCertificate(s.T(), s.ca, pub, ssh.UserCert, principals, s.ttl)
+			s.signed++
+
+			data = map[string]string{"signed_key": string(ssh.MarshalAuthorizedKey(cert))}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+
+	client, err := NewClient(s.server.URL, nil)
+	require.NoError(s.T(), err)
+	s.ssh = client.SSH()
+}
+
+func (s *SSHSignerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *SSHSignerTestSuite) TestSignKey() {
+	signer := newTestSSHSigner(s.T())
+
+	cert, err := s.ssh.SignKey("test", signer, SSHSignOptions{ValidPrincipals: []string{"ubuntu"}})
+	require.NoError(s.T(), err)
+	s.Equal(signer.PublicKey().Marshal(), cert.Key.Marshal())
+	s.Equal(s.ca.PublicKey().Marshal(), cert.SignatureKey.Marshal())
+	s.Equal([]string{"ubuntu"}, cert.ValidPrincipals)
+}
+
+func (s *SSHSignerTestSuite) TestCertSigner() {
+	signer := newTestSSHSigner(s.T())
+
+	var rotated []*ssh.Certificate
+	certSigner, err := NewSSHCertSigner(
+		s.ssh, "test", signer, SSHSignOptions{ValidPrincipals: []string{"ubuntu"}},
+		WithSSHRotationHook(func(cert *ssh.Certificate) { rotated = append(rotated, cert) }),
+	)
+	require.NoError(s.T(), err)
+	s.Len(rotated, 1)
+	s.Equal(certSigner.Certificate(), certSigner.PublicKey())
+
+	// the certificate lifetime is 61 minutes, it is renewed after 2/3 of it
+	certSigner.now = func() time.Time { return time.Unix(int64(certSigner.Certificate().ValidAfter), 0) }
+	s.Equal(40*time.Minute+40*time.Second, certSigner.renewIn())
+
+	certSigner.now = func() time.Time { return time.Unix(int64(certSigner.Certificate().ValidBefore), 0) }
+	s.Zero(certSigner.renewIn())
+
+	require.NoError(s.T(), certSigner.Renew())
+	s.Len(rotated, 2)
+	s.Equal(2, s.signed)
+
+	// the signer authenticates using the certificate
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(s.ca.PublicKey().Marshal())
+		},
+	}
+
+	data := []byte("session data")
+	sig, err := certSigner.Sign(rand.Reader, data)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), certSigner.PublicKey().Verify(data, sig))
+
+	_, err = checker.Authenticate(dummyConnMetadata("ubuntu"), certSigner.PublicKey())
+	s.NoError(err)
+}
+
+func (s *SSHSignerTestSuite) TestHostKeyCallback() {
+	callback, err := s.ssh.HostKeyCallback()
+	require.NoError(s.T(), err)
+
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 22}
+	host := newTestSSHSigner(s.T())
+
+	cert := signTestSSHCertificate(s.T(), s.ca, host.PublicKey(), ssh.HostCert, []string{"web.example.com"}, time.Hour)
+	s.NoError(callback("web.example.com:22", addr, cert))
+
+	// wrong host name
+	s.Error(callback("db.example.com:22", addr, cert))
+
+	// user certificates are no host certificates
+	cert = signTestSSHCertificate(s.T(), s.ca, host.PublicKey(), ssh.UserCert, []string{"web.example.com"}, time.Hour)
+	s.Error(callback("web.example.com:22", addr, cert))
+
+	// signed by another CA
+	cert = signTestSSHCertificate(s.T(), newTestSSHSigner(s.T()), host.PublicKey(), ssh.HostCert, []string{"web.example.com"}, time.Hour)
+	s.Error(callback("web.example.com:22", addr, cert))
+
+	// plain host keys are rejected
+	s.Error(callback("web.example.com:22", addr, host.PublicKey()))
+}
+
+type dummyConnMetadata string
+
+func (m dummyConnMetadata) User() string          { return string(m) }
+func (m dummyConnMetadata) SessionID() []byte     { return nil }
+func (m dummyConnMetadata) ClientVersion() []byte { return nil }
+func (m dummyConnMetadata) ServerVersion() []byte { return nil }
+func (m dummyConnMetadata) RemoteAddr() net.Addr  { return nil }
+func (m dummyConnMetadata) LocalAddr() net.Addr   { return nil }
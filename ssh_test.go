@@ -0,0 +1,220 @@
+package vault
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/mittwald/vaultgo/test/testdata"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHTestSuite struct {
+	suite.Suite
+	client *SSH
+}
+
+func TestSSHTestSuite(t *testing.T) {
+	for _, v := range testdata.VaultVersions {
+		require.NoError(t, testdata.Init(context.Background(), v))
+
+		t.Logf("using vault uri %v", testdata.Vault.URI())
+		client, _ := NewClient(testdata.Vault.URI(), WithCaPath(""))
+		client.SetToken(testdata.Vault.Token())
+
+		sshTestSuite := new(SSHTestSuite)
+		sshTestSuite.client = client.SSH()
+
+		suite.Run(t, sshTestSuite)
+	}
+}
+
+func (s *SSHTestSuite) SetupSuite() {
+	_, err := s.client.GenerateCA(SSHConfigCARequest{})
+	require.NoError(s.T(), err)
+}
+
+func TestSSHSignOptionsMarshal(t *testing.T) {
+	body, err := json.Marshal(SSHSignOptions{
+		PublicKey:       "ssh-ed25519 AAAA",
+		ValidPrincipals: []string{"ubuntu", "deploy"},
+		Extensions:      map[string]string{"permit-pty": ""},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"public_key":"ssh-ed25519 AAAA","valid_principals":"ubuntu,deploy","extensions":{"permit-pty":""}}`, string(body))
+
+	body, err = json.Marshal(SSHSignOptions{PublicKey: "ssh-ed25519 AAAA"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"public_key":"ssh-ed25519 AAAA"}`, string(body))
+}
+
+func (s *SSHTestSuite) newPublicKey() string {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(s.T(), err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(s.T(), err)
+
+	return string(ssh.MarshalAuthorizedKey(sshPub))
+}
+
+func (s *SSHTestSuite) parseCertificate(signedKey string) *ssh.Certificate {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(signedKey))
+	require.NoError(s.T(), err)
+
+	cert, ok := pub.(*ssh.Certificate)
+	require.True(s.T(), ok)
+
+	return cert
+}
+
+func (s *SSHTestSuite) TestSignUserAndHostKeys() {
+	require.NoError(s.T(), s.client.CreateOrUpdateRole("user", SSHRoleRequest{
+		KeyType:                SSHKeyTypeCA,
+		AllowedUsers:           "*",
+		AllowUserCertificates:  BoolPtr(true),
+		AllowUserKeyIDs:        BoolPtr(true),
+		AllowedExtensions:      "permit-pty",
+		AllowedCriticalOptions: "force-command",
+		MaxTTL:                 "24h",
+	}))
+	require.NoError(s.T(), s.client.CreateOrUpdateRole("host", SSHRoleRequest{
+		KeyType:               SSHKeyTypeCA,
+		AllowHostCertificates: BoolPtr(true),
+		AllowedDomains:        "example.com",
+		AllowSubdomains:       BoolPtr(true),
+	}))
+
+	caKey, err := s.client.GetVaultPubKey()
+	require.NoError(s.T(), err)
+	ca, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caKey))
+	require.NoError(s.T(), err)
+
+	signed, err := s.client.Sign("user", SSHSignOptions{
+		PublicKey:       s.newPublicKey(),
+		ValidPrincipals: []string{"ubuntu", "deploy"},
+		TTL:             "1h",
+		KeyID:           "deploy-key",
+		Extensions:      map[string]string{"permit-pty": ""},
+		CriticalOptions: map[string]string{"force-command": "/bin/true"},
+	})
+	require.NoError(s.T(), err)
+
+	cert := s.parseCertificate(signed.Data.SignedKey)
+	s.Equal(uint32(ssh.UserCert), cert.CertType)
+	s.Equal([]string{"ubuntu", "deploy"}, cert.ValidPrincipals)
+	s.Equal("deploy-key", cert.KeyId)
+	s.Equal(map[string]string{"permit-pty": ""}, cert.Extensions)
+	s.Equal(map[string]string{"force-command": "/bin/true"}, cert.CriticalOptions)
+	s.Equal(ca.Marshal(), cert.SignatureKey.Marshal())
+
+	signed, err = s.client.SignHostKey("host", SSHSignOptions{
+		PublicKey:       s.newPublicKey(),
+		ValidPrincipals: []string{"web.example.com"},
+	})
+	require.NoError(s.T(), err)
+
+	cert = s.parseCertificate(signed.Data.SignedKey)
+	s.Equal(uint32(ssh.HostCert), cert.CertType)
+	s.Equal([]string{"web.example.com"}, cert.ValidPrincipals)
+}
+
+func (s *SSHTestSuite) TestImportCA() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.client.DeleteCA())
+
+	_, err = s.client.ImportCA(
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		string(ssh.MarshalAuthorizedKey(pub)),
+	)
+	require.NoError(s.T(), err)
+
+	caKey, err := s.client.GetVaultPubKey()
+	require.NoError(s.T(), err)
+	s.Equal(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))), strings.TrimSpace(caKey))
+}
+
+func (s *SSHTestSuite) TestOTP() {
+	require.NoError(s.T(), s.client.CreateOrUpdateRole("otp", SSHRoleRequest{
+		KeyType:     SSHKeyTypeOTP,
+		DefaultUser: "admin",
+		CIDRList:    "10.0.0.0/24",
+		Port:        2222,
+	}))
+
+	lookup, err := s.client.Lookup(SSHLookupRequest{IP: "10.0.0.5"})
+	require.NoError(s.T(), err)
+	s.Equal([]string{"otp"}, lookup.Data.Roles)
+
+	creds, err := s.client.Creds("otp", SSHCredsOptions{IP: "10.0.0.5"})
+	require.NoError(s.T(), err)
+	s.Equal(SSHKeyTypeOTP, creds.Data.KeyType)
+	s.Equal("admin", creds.Data.Username)
+	s.Equal(2222, creds.Data.Port)
+	s.NotEmpty(creds.Data.Key)
+
+	verified, err := s.client.Verify(creds.Data.Key)
+	require.NoError(s.T(), err)
+	s.Equal("otp", verified.Data.RoleName)
+	s.Equal("10.0.0.5", verified.Data.IP)
+
+	// an otp can only be used once
+	_, err = s.client.Verify(creds.Data.Key)
+	s.Error(err)
+
+	_, err = s.client.Creds("otp", SSHCredsOptions{IP: "192.168.0.1"})
+	s.Error(err)
+}
+
+func (s *SSHTestSuite) TestRoleRoundTrip() {
+	require.NoError(s.T(), s.client.CreateOrUpdateRole("ca", SSHRoleRequest{
+		KeyType:               SSHKeyTypeCA,
+		AllowedUsers:          "ubuntu,deploy",
+		AllowUserCertificates: BoolPtr(true),
+		DefaultExtensions:     map[string]string{"permit-pty": ""},
+		TTL:                   "1h",
+		MaxTTL:                "24h",
+	}))
+
+	role, err := s.client.ReadRole("ca")
+	require.NoError(s.T(), err)
+	s.Equal(SSHKeyTypeCA, role.Data.KeyType)
+	s.Equal("ubuntu,deploy", role.Data.AllowedUsers)
+	s.True(role.Data.AllowUserCertificates)
+	s.False(role.Data.AllowHostCertificates)
+	s.Equal(map[string]string{"permit-pty": ""}, role.Data.DefaultExtensions)
+	s.Equal(3600, role.Data.TTL)
+	s.Equal(86400, role.Data.MaxTTL)
+
+	roles, err := s.client.ListRoles()
+	require.NoError(s.T(), err)
+	s.Contains(roles.Data.Keys, "ca")
+	s.Equal(SSHKeyTypeCA, roles.Data.KeyInfo["ca"].KeyType)
+
+	require.NoError(s.T(), s.client.DeleteRole("ca"))
+
+	_, err = s.client.ReadRole("ca")
+	s.Error(err)
+}
+
+func (s *SSHTestSuite) TestReadNotFound() {
+	_, err := s.client.ReadRole("not-found")
+	s.ErrorIs(err, ErrNotFound)
+
+	role, err := s.client.ReadRoleOrNil("not-found")
+	require.NoError(s.T(), err)
+	s.Nil(role)
+}
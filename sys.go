@@ -0,0 +1,61 @@
+package vault
+
+import "encoding/base64"
+
+type Sys struct {
+	Service
+}
+
+func (c *Client) Sys() *Sys {
+	return c.SysWithMountPoint("sys")
+}
+
+func (c *Client) SysWithMountPoint(mountPoint string) *Sys {
+	return &Sys{
+		Service: Service{
+			client:     c,
+			MountPoint: mountPoint,
+		},
+	}
+}
+
+// SysRandomOptions are the parameters of sys/tools/random, which are the same as the ones of Transit.Random
+type SysRandomOptions = TransitRandomOptions
+
+type SysRandomResponse = TransitRandomResponse
+
+// Random returns random bytes generated by the sys/tools endpoint, see Transit.Random
+func (s *Sys) Random(opts SysRandomOptions) ([]byte, error) {
+	res := &SysRandomResponse{}
+
+	err := s.client.Write([]string{"v1", s.MountPoint, "tools", "random"}, opts, res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFormatted(opts.Format, res.Data.RandomBytes)
+}
+
+// SysHashOptions are the parameters of sys/tools/hash, which are the same as the ones of Transit.Hash
+type SysHashOptions = TransitHashOptions
+
+type SysHashResponse = TransitHashResponse
+
+// Hash returns the digest of opts.Input calculated by the sys/tools endpoint, see Transit.Hash
+func (s *Sys) Hash(opts SysHashOptions) ([]byte, error) {
+	res := &SysHashResponse{}
+
+	opts.Input = base64.StdEncoding.EncodeToString([]byte(opts.Input))
+
+	// unlike random, hash returns hex by default
+	if opts.Format == "" {
+		opts.Format = "hex"
+	}
+
+	err := s.client.Write([]string{"v1", s.MountPoint, "tools", "hash"}, opts, res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFormatted(opts.Format, res.Data.Sum)
+}
@@ -0,0 +1,118 @@
+package vault
+
+import (
+	"net/http"
+)
+
+type SysNamespaceRequest struct {
+	CustomMetadata map[string]string `json:"custom_metadata,omitempty"`
+}
+
+type SysNamespaceResponse struct {
+	Data struct {
+		ID             string            `json:"id"`
+		Path           string            `json:"path"`
+		CustomMetadata map[string]string `json:"custom_metadata"`
+	} `json:"data"`
+}
+
+type SysNamespaceListResponse struct {
+	Data struct {
+		Keys    []string `json:"keys"`
+		KeyInfo map[string]struct {
+			ID             string            `json:"id"`
+			Path           string            `json:"path"`
+			CustomMetadata map[string]string `json:"custom_metadata"`
+		} `json:"key_info"`
+	} `json:"data"`
+}
+
+// ListNamespaces lists the child namespaces of the client namespace, see Client.InNamespace
+func (s *Sys) ListNamespaces() (*SysNamespaceListResponse, error) {
+	response := &SysNamespaceListResponse{}
+	err := s.client.List(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"namespaces",
+		}, nil, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *Sys) CreateNamespace(path string, opts SysNamespaceRequest) (*SysNamespaceResponse, error) {
+	response := &SysNamespaceResponse{}
+	err := s.client.Write(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"namespaces",
+			path,
+		}, opts, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *Sys) ReadNamespace(path string) (*SysNamespaceResponse, error) {
+	response := &SysNamespaceResponse{}
+	err := s.client.Read(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"namespaces",
+			path,
+		}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateNamespace merges opts.CustomMetadata into the custom metadata of the namespace.
+// It is supported since vault 1.12.
+func (s *Sys) UpdateNamespace(path string, opts SysNamespaceRequest) (*SysNamespaceResponse, error) {
+	response := &SysNamespaceResponse{}
+	err := s.client.Request(
+		"PATCH",
+		[]string{
+			"v1",
+			s.MountPoint,
+			"namespaces",
+			path,
+		}, opts, response, &RequestOptions{
+			Headers: http.Header{"Content-Type": []string{"application/merge-patch+json"}},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteNamespace deletes an empty namespace, child namespaces have to be deleted first
+func (s *Sys) DeleteNamespace(path string) error {
+	err := s.client.Delete(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"namespaces",
+			path,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
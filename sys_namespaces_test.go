@@ -0,0 +1,125 @@
+package vault
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordedRequest struct {
+	Method      string
+	Path        string
+	Namespace   string
+	Token       string
+	ContentType string
+	Body        string
+}
+
+type NamespacesTestSuite struct {
+	suite.Suite
+	requests []recordedRequest
+	client   *Client
+	server   *httptest.Server
+}
+
+func TestNamespacesTestSuite(t *testing.T) {
+	suite.Run(t, new(NamespacesTestSuite))
+}
+
+func (s *NamespacesTestSuite) SetupTest() {
+	s.requests = nil
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.requests = append(s.requests, recordedRequest{
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			Namespace:   r.Header.Get("X-Vault-Namespace"),
+			Token:       r.Header.Get("X-Vault-Token"),
+			ContentType: r.Header.Get("Content-Type"),
+			Body:        string(body),
+		})
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"id": "abc", "path": "tenant-a/", "keys": []string{"tenant-a/"}},
+		})
+	}))
+
+	var err error
+	s.client, err = NewClient(s.server.URL, nil, WithAuthToken("token"), WithNamespace("root-ns"))
+	require.NoError(s.T(), err)
+}
+
+func (s *NamespacesTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *NamespacesTestSuite) lastRequest() recordedRequest {
+	require.NotEmpty(s.T(), s.requests)
+
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *NamespacesTestSuite) TestNamespaceOverrides() {
+	s.Equal("root-ns", s.client.Namespace())
+
+	_, err := s.client.SSH().GetVaultPubKey()
+	require.NoError(s.T(), err)
+	s.Equal("root-ns", s.lastRequest().Namespace)
+
+	tenant := s.client.InNamespace("tenant-a")
+	s.Equal("tenant-a", tenant.Namespace())
+
+	_, err = tenant.SSHWithMountPoint("tenant-ssh").GetVaultPubKey()
+	require.NoError(s.T(), err)
+	s.Equal(recordedRequest{Method: "GET", Path: "/v1/tenant-ssh/config/ca", Namespace: "tenant-a", Token: "token"}, s.lastRequest())
+
+	// the token is shared
+	s.client.SetToken("new-token")
+	_, err = tenant.SSH().GetVaultPubKey()
+	require.NoError(s.T(), err)
+	s.Equal("new-token", s.lastRequest().Token)
+
+	// the parent client is not changed
+	_, err = s.client.SSH().GetVaultPubKey()
+	require.NoError(s.T(), err)
+	s.Equal("root-ns", s.lastRequest().Namespace)
+
+	err = tenant.Read([]string{"v1", "sys", "health"}, nil, &RequestOptions{Namespace: "tenant-b"})
+	require.NoError(s.T(), err)
+	s.Equal("tenant-b", s.lastRequest().Namespace)
+}
+
+func (s *NamespacesTestSuite) TestCRUD() {
+	sys := s.client.Sys()
+
+	created, err := sys.CreateNamespace("tenant-a", SysNamespaceRequest{CustomMetadata: map[string]string{"team": "a"}})
+	require.NoError(s.T(), err)
+	s.Equal("abc", created.Data.ID)
+	s.Equal("POST", s.lastRequest().Method)
+	s.Equal("/v1/sys/namespaces/tenant-a", s.lastRequest().Path)
+	s.JSONEq(`{"custom_metadata":{"team":"a"}}`, s.lastRequest().Body)
+
+	_, err = sys.ReadNamespace("tenant-a")
+	require.NoError(s.T(), err)
+	s.Equal("GET", s.lastRequest().Method)
+
+	list, err := sys.ListNamespaces()
+	require.NoError(s.T(), err)
+	s.Equal([]string{"tenant-a/"}, list.Data.Keys)
+	s.Equal("/v1/sys/namespaces", s.lastRequest().Path)
+
+	_, err = sys.UpdateNamespace("tenant-a", SysNamespaceRequest{CustomMetadata: map[string]string{"team": "b"}})
+	require.NoError(s.T(), err)
+	s.Equal("PATCH", s.lastRequest().Method)
+	s.Equal("application/merge-patch+json", s.lastRequest().ContentType)
+
+	require.NoError(s.T(), s.client.InNamespace("root-ns/tenant-a").Sys().DeleteNamespace("team-b"))
+	s.Equal(recordedRequest{Method: "DELETE", Path: "/v1/sys/namespaces/team-b", Namespace: "root-ns/tenant-a", Token: "token"}, s.lastRequest())
+}
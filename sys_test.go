@@ -0,0 +1,35 @@
+package vault
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysHashFormat(t *testing.T) {
+	sum := sha256.Sum256([]byte("test"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts := SysHashOptions{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil || opts.Format != "hex" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"sum": hex.EncodeToString(sum[:])}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	// vault returns hex if no format is given
+	res, err := client.Sys().Hash(SysHashOptions{Input: "test"})
+	require.NoError(t, err)
+	require.Equal(t, sum[:], res)
+}
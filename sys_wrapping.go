@@ -0,0 +1,141 @@
+package vault
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type WrapInfo struct {
+	Token           string    `json:"token"`
+	Accessor        string    `json:"accessor"`
+	TTL             int       `json:"ttl"`
+	CreationTime    time.Time `json:"creation_time"`
+	CreationPath    string    `json:"creation_path"`
+	WrappedAccessor string    `json:"wrapped_accessor"`
+}
+
+// WrappedResponse is the response of requests sent with RequestOptions.WrapTTL
+type WrappedResponse struct {
+	WrapInfo *WrapInfo `json:"wrap_info"`
+}
+
+// RequestWrapped sends a request whose response is wrapped for wrapTTL, the returned token can be passed to Sys.WrappingUnwrap
+func (c *Client) RequestWrapped(method string, path []string, body interface{}, wrapTTL string, opts *RequestOptions) (*WrapInfo, error) {
+	wrapOpts := RequestOptions{}
+	if opts != nil {
+		wrapOpts = *opts
+	}
+	wrapOpts.WrapTTL = wrapTTL
+
+	response := &WrappedResponse{}
+	if err := c.Request(method, path, body, response, &wrapOpts); err != nil {
+		return nil, err
+	}
+
+	if response.WrapInfo == nil {
+		return nil, errors.New("response was not wrapped")
+	}
+
+	return response.WrapInfo, nil
+}
+
+// Wrap wraps arbitrary data, it is returned as Data of the unwrapped response. wrapTTL defaults to 5 minutes.
+func (s *Sys) Wrap(data map[string]interface{}, wrapTTL string) (*WrapInfo, error) {
+	return s.client.RequestWrapped(
+		"POST",
+		[]string{
+			"v1",
+			s.MountPoint,
+			"wrapping",
+			"wrap",
+		}, data, wrapTTL, nil,
+	)
+}
+
+// WrappingUnwrap decodes the response wrapped in token into response, the token can only be unwrapped once.
+// The wrapping token is used to authenticate, the client doesn't need a token for this.
+func (s *Sys) WrappingUnwrap(token string, response interface{}) error {
+	err := s.client.Write(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"wrapping",
+			"unwrap",
+		}, nil, response, &RequestOptions{Token: token, SkipRenewal: true},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnwrapInto unwraps token and decodes the Data of the wrapped response into T
+func UnwrapInto[T any](s *Sys, token string) (*T, error) {
+	response := &struct {
+		Data *T `json:"data"`
+	}{}
+
+	if err := s.WrappingUnwrap(token, response); err != nil {
+		return nil, err
+	}
+
+	if response.Data == nil {
+		return nil, errors.New("wrapped response contains no data")
+	}
+
+	return response.Data, nil
+}
+
+type SysWrappingTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type SysWrappingLookupResponse struct {
+	Data struct {
+		CreationPath string    `json:"creation_path"`
+		CreationTime time.Time `json:"creation_time"`
+		CreationTTL  int       `json:"creation_ttl"`
+	} `json:"data"`
+}
+
+// WrappingLookup returns the properties of a wrapping token without unwrapping it
+func (s *Sys) WrappingLookup(token string) (*SysWrappingLookupResponse, error) {
+	response := &SysWrappingLookupResponse{}
+	err := s.client.Write(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"wrapping",
+			"lookup",
+		}, SysWrappingTokenRequest{Token: token}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// WrappingRewrap moves the wrapped response to a new token with the original TTL, the old token is revoked
+func (s *Sys) WrappingRewrap(token string) (*WrapInfo, error) {
+	response := &WrappedResponse{}
+	err := s.client.Write(
+		[]string{
+			"v1",
+			s.MountPoint,
+			"wrapping",
+			"rewrap",
+		}, SysWrappingTokenRequest{Token: token}, response, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.WrapInfo == nil {
+		return nil, errors.New("response was not wrapped")
+	}
+
+	return response.WrapInfo, nil
+}
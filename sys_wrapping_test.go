@@ -0,0 +1,124 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WrappingTestSuite struct {
+	suite.Suite
+	wrapped map[string]map[string]interface{}
+	client  *Client
+	server  *httptest.Server
+}
+
+func TestWrappingTestSuite(t *testing.T) {
+	suite.Run(t, new(WrappingTestSuite))
+}
+
+// SetupTest starts a server wrapping request bodies of sys/wrapping/wrap in tokens valid for a single unwrap
+func (s *WrappingTestSuite) SetupTest() {
+	s.wrapped = make(map[string]map[string]interface{})
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+
+		switch r.URL.Path {
+		case "/v1/sys/wrapping/wrap":
+			if r.Header.Get("X-Vault-Wrap-TTL") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"wrap ttl missing"}})
+
+				return
+			}
+
+			data := map[string]interface{}{}
+			require.NoError(s.T(), json.NewDecoder(r.Body).Decode(&data))
+
+			token := "wrapping-token-" + r.Header.Get("X-Vault-Wrap-TTL")
+			s.wrapped[token] = data
+
+			response = map[string]interface{}{
+				"wrap_info": map[string]interface{}{
+					"token":         token,
+					"ttl":           300,
+					"creation_path": "sys/wrapping/wrap",
+					"creation_time": "2022-11-02T10:00:00Z",
+				},
+			}
+		case "/v1/sys/wrapping/unwrap":
+			token := r.Header.Get("X-Vault-Token")
+
+			data, ok := s.wrapped[token]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"wrapping token is not valid or does not exist"}})
+
+				return
+			}
+			delete(s.wrapped, token)
+
+			response = map[string]interface{}{"data": data}
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+
+	var err error
+	s.client, err = NewClient(s.server.URL, nil, WithAuthToken("client-token"))
+	require.NoError(s.T(), err)
+}
+
+func (s *WrappingTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *WrappingTestSuite) TestWrapAndUnwrap() {
+	// the api package defaults to 5m for sys/wrapping/wrap
+	wrapInfo, err := s.client.Sys().Wrap(map[string]interface{}{"username": "admin"}, "")
+	require.NoError(s.T(), err)
+	s.Equal("wrapping-token-5m", wrapInfo.Token)
+
+	wrapInfo, err = s.client.Sys().Wrap(map[string]interface{}{"username": "admin", "password": "secret"}, "10m")
+	require.NoError(s.T(), err)
+	s.Equal("wrapping-token-10m", wrapInfo.Token)
+	s.Equal(300, wrapInfo.TTL)
+	s.Equal("sys/wrapping/wrap", wrapInfo.CreationPath)
+	s.Equal(2022, wrapInfo.CreationTime.Year())
+
+	type credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	creds, err := UnwrapInto[credentials](s.client.Sys(), wrapInfo.Token)
+	require.NoError(s.T(), err)
+	s.Equal(&credentials{Username: "admin", Password: "secret"}, creds)
+
+	// the client token is kept
+	s.Equal("client-token", s.client.Token())
+
+	// tokens can only be unwrapped once
+	_, err = UnwrapInto[credentials](s.client.Sys(), wrapInfo.Token)
+	s.Error(err)
+}
+
+func (s *WrappingTestSuite) TestRequestWrapped() {
+	_, err := s.client.RequestWrapped("POST", []string{"v1", "sys", "wrapping", "unwrap"}, nil, "5m", &RequestOptions{Token: "invalid"})
+	s.Error(err)
+
+	wrapInfo, err := s.client.RequestWrapped("POST", []string{"v1", "sys", "wrapping", "wrap"}, map[string]string{"key": "value"}, "1h", nil)
+	require.NoError(s.T(), err)
+	s.Equal("wrapping-token-1h", wrapInfo.Token)
+
+	response := &struct {
+		Data map[string]string `json:"data"`
+	}{}
+	require.NoError(s.T(), s.client.Sys().WrappingUnwrap(wrapInfo.Token, response))
+	s.Equal(map[string]string{"key": "value"}, response.Data)
+}
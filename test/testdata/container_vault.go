@@ -119,5 +119,31 @@ func InitVaultContainer(ctx context.Context, version string) (*VaultContainer, e
 		return nil, err
 	}
 
+	// PKI mount with a root CA and a role allowing any name
+	for _, cmd := range [][]string{
+		{"vault", "secrets", "enable", "pki"},
+		{"vault", "secrets", "tune", "-max-lease-ttl=87600h", "pki"},
+		{"vault", "write", "pki/root/generate/internal", "common_name=vaultgo test root", "ttl=87600h"},
+		{"vault", "write", "pki/roles/test", "allow_any_name=true", "max_ttl=72h"},
+	} {
+		_, _, err = vc.container.Exec(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// SSH mount
+	_, _, err = vc.container.Exec(
+		ctx, []string{
+			"vault",
+			"secrets",
+			"enable",
+			"ssh",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return vc, nil
 }
@@ -8,12 +8,12 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
-
-	"github.com/hashicorp/vault/api"
 )
 
 type Transit struct {
 	Service
+
+	cache *TransitDecryptCache
 }
 
 func (c *Client) Transit() *Transit {
@@ -29,6 +29,14 @@ func (c *Client) TransitWithMountPoint(mountPoint string) *Transit {
 	}
 }
 
+// WithDecryptCache enables caching of decrypted plaintexts. Cached entries of a key are invalidated
+// when the key is rotated, deleted or its min_decryption_version is updated through this service.
+func (t *Transit) WithDecryptCache(cache *TransitDecryptCache) *Transit {
+	t.cache = cache
+
+	return t
+}
+
 type TransitCreateOptions struct {
 	ConvergentEncryption *bool  `json:"convergent_encryption,omitempty"`
 	Derived              *bool  `json:"derived,omitempty"`
@@ -54,7 +62,7 @@ type TransitReadResponseData struct {
 	Name                 string              `json:"name"`
 	Type                 string              `json:"type"`
 	Keys                 map[int]interface{} `json:"keys"`
-	MinDecryptionVersion int                 `json:"min_decrytion_version"`
+	MinDecryptionVersion int                 `json:"min_decryption_version"`
 	MinEncryptionVersion int                 `json:"min_encryption_version"`
 	LatestVersion        int                 `json:"latest_version"`
 	DeletionAllowed      bool                `json:"deletion_allowed"`
@@ -72,12 +80,17 @@ func (t *Transit) Read(key string) (*TransitReadResponse, error) {
 
 	err := t.client.Read([]string{"v1", t.MountPoint, "keys", url.PathEscape(key)}, readRes, nil)
 	if err != nil {
-		return nil, err
+		return nil, t.mapError(err)
 	}
 
 	return readRes, nil
 }
 
+// ReadOrNil is like Read, but returns nil if the key doesn't exist
+func (t *Transit) ReadOrNil(key string) (*TransitReadResponse, error) {
+	return orNil(t.Read(key))
+}
+
 type TransitListResponse struct {
 	Data struct {
 		Keys []string `json:"keys"`
@@ -101,6 +114,10 @@ func (t *Transit) Delete(key string) error {
 		return err
 	}
 
+	if t.cache != nil {
+		t.cache.invalidate(t.client.Namespace(), t.MountPoint, key, 0)
+	}
+
 	return nil
 }
 
@@ -116,7 +133,7 @@ func (t *Transit) ForceDelete(key string) error {
 }
 
 type TransitUpdateOptions struct {
-	MinDecryptionVersion int   `json:"min_decrytion_version,omitempty"`
+	MinDecryptionVersion int   `json:"min_decryption_version,omitempty"`
 	MinEncryptionVersion int   `json:"min_encryption_version,omitempty"`
 	DeletionAllowed      *bool `json:"deletion_allowed,omitempty"`
 	Exportable           *bool `json:"exportable,omitempty"`
@@ -129,6 +146,10 @@ func (t *Transit) Update(key string, opts TransitUpdateOptions) error {
 		return err
 	}
 
+	if t.cache != nil && opts.MinDecryptionVersion > 0 {
+		t.cache.invalidate(t.client.Namespace(), t.MountPoint, key, opts.MinDecryptionVersion)
+	}
+
 	return nil
 }
 
@@ -138,6 +159,10 @@ func (t *Transit) Rotate(key string) error {
 		return err
 	}
 
+	if t.cache != nil {
+		t.cache.invalidate(t.client.Namespace(), t.MountPoint, key, 0)
+	}
+
 	return nil
 }
 
@@ -164,7 +189,7 @@ func (t *Transit) Export(key string, opts TransitExportOptions) (*TransitExportR
 
 	err := t.client.Read(path, res, nil)
 	if err != nil {
-		return nil, err
+		return nil, t.mapError(err)
 	}
 
 	return res, nil
@@ -188,11 +213,15 @@ func (t *Transit) KeyExists(key string) (bool, error) {
 type TransitBatchCiphertext struct {
 	Ciphertext string `json:"ciphertext"`
 	Context    string `json:"context,omitempty"`
+	// Error is set in batch results if the item failed, the other items are still processed by vault
+	Error string `json:"error,omitempty"`
 }
 
 type TransitBatchPlaintext struct {
 	Plaintext string `json:"plaintext"`
 	Context   string `json:"context,omitempty"`
+	// Error is set in batch results if the item failed, the other items are still processed by vault
+	Error string `json:"error,omitempty"`
 }
 
 type TransitEncryptOptions struct {
@@ -266,6 +295,15 @@ type TransitDecryptResponse struct {
 func (t *Transit) Decrypt(key string, opts *TransitDecryptOptions) (*TransitDecryptResponse, error) {
 	res := &TransitDecryptResponse{}
 
+	var cacheKey transitCacheKey
+	if t.cache != nil {
+		cacheKey = t.cacheKey(key, opts.Ciphertext, opts.Context)
+		if plaintext, ok := t.cache.get(cacheKey); ok {
+			res.Data.Plaintext = plaintext
+			return res, nil
+		}
+	}
+
 	err := t.client.Write([]string{"v1", t.MountPoint, "decrypt", url.PathEscape(key)}, opts, res, nil)
 	if err != nil {
 		return nil, t.mapError(err)
@@ -278,6 +316,10 @@ func (t *Transit) Decrypt(key string, opts *TransitDecryptOptions) (*TransitDecr
 
 	res.Data.Plaintext = string(blob)
 
+	if t.cache != nil {
+		t.cache.add(cacheKey, res.Data.Plaintext)
+	}
+
 	return res, nil
 }
 
@@ -292,6 +334,14 @@ type TransitDecryptResponseBatch struct {
 }
 
 func (t *Transit) DecryptBatch(key string, opts TransitDecryptOptionsBatch) (*TransitDecryptResponseBatch, error) {
+	if t.cache != nil {
+		return t.decryptBatchCached(key, opts)
+	}
+
+	return t.decryptBatch(key, opts)
+}
+
+func (t *Transit) decryptBatch(key string, opts TransitDecryptOptionsBatch) (*TransitDecryptResponseBatch, error) {
 	res := &TransitDecryptResponseBatch{}
 
 	err := t.client.Write([]string{"v1", t.MountPoint, "decrypt", key}, opts, res, nil)
@@ -311,6 +361,65 @@ func (t *Transit) DecryptBatch(key string, opts TransitDecryptOptionsBatch) (*Tr
 	return res, nil
 }
 
+// decryptBatchCached only sends the ciphertexts missing in the cache to vault
+func (t *Transit) decryptBatchCached(key string, opts TransitDecryptOptionsBatch) (*TransitDecryptResponseBatch, error) {
+	res := &TransitDecryptResponseBatch{}
+	res.Data.BatchResults = make([]TransitBatchPlaintext, len(opts.BatchInput))
+
+	var missing []int
+	for i, in := range opts.BatchInput {
+		res.Data.BatchResults[i].Context = in.Context
+
+		if plaintext, ok := t.cache.get(t.cacheKey(key, in.Ciphertext, in.Context)); ok {
+			res.Data.BatchResults[i].Plaintext = plaintext
+		} else {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	missingOpts := TransitDecryptOptionsBatch{
+		BatchInput: make([]TransitBatchCiphertext, len(missing)),
+	}
+	for i, idx := range missing {
+		missingOpts.BatchInput[i] = opts.BatchInput[idx]
+	}
+
+	missingRes, err := t.decryptBatch(key, missingOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missingRes.Data.BatchResults) != len(missing) {
+		return nil, fmt.Errorf("expected %d batch results, got %d", len(missing), len(missingRes.Data.BatchResults))
+	}
+
+	for i, idx := range missing {
+		in, out := opts.BatchInput[idx], missingRes.Data.BatchResults[i]
+		res.Data.BatchResults[idx].Plaintext = out.Plaintext
+		res.Data.BatchResults[idx].Error = out.Error
+
+		if out.Error == "" {
+			t.cache.add(t.cacheKey(key, in.Ciphertext, in.Context), out.Plaintext)
+		}
+	}
+
+	return res, nil
+}
+
+func (t *Transit) cacheKey(key string, ciphertext string, context string) transitCacheKey {
+	return transitCacheKey{
+		namespace:  t.client.Namespace(),
+		mountPoint: t.MountPoint,
+		key:        key,
+		ciphertext: ciphertext,
+		context:    context,
+	}
+}
+
 type TransitSignOptions struct {
 	Input               string `json:"input"`
 	KeyVersion          *int   `json:"key_version,omitempty"`
@@ -454,6 +563,62 @@ func (t *Transit) VerifyBatch(key string, opts *TransitVerifyOptionsBatch) (*Tra
 	return res, nil
 }
 
+type TransitRandomOptions struct {
+	Bytes  int    `json:"bytes,omitempty"`
+	Format string `json:"format,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+type TransitRandomResponse struct {
+	Data struct {
+		RandomBytes string `json:"random_bytes"`
+	} `json:"data"`
+}
+
+// Random returns random bytes generated by Vault. Source selects the entropy source ("platform", "seal" or "all"),
+// Format only controls the transport encoding, the returned bytes are always decoded.
+func (t *Transit) Random(opts TransitRandomOptions) ([]byte, error) {
+	res := &TransitRandomResponse{}
+
+	err := t.client.Write([]string{"v1", t.MountPoint, "random"}, opts, res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFormatted(opts.Format, res.Data.RandomBytes)
+}
+
+type TransitHashOptions struct {
+	Input     string `json:"input"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+type TransitHashResponse struct {
+	Data struct {
+		Sum string `json:"sum"`
+	} `json:"data"`
+}
+
+// Hash returns the digest of opts.Input calculated by Vault using opts.Algorithm (defaults to "sha2-256").
+func (t *Transit) Hash(opts TransitHashOptions) ([]byte, error) {
+	res := &TransitHashResponse{}
+
+	opts.Input = base64.StdEncoding.EncodeToString([]byte(opts.Input))
+
+	// unlike random, hash returns hex by default
+	if opts.Format == "" {
+		opts.Format = "hex"
+	}
+
+	err := t.client.Write([]string{"v1", t.MountPoint, "hash"}, opts, res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFormatted(opts.Format, res.Data.Sum)
+}
+
 // DecodeCipherText gets payload from vault ciphertext format (removes "vault:v<ver>:" prefix)
 func DecodeCipherText(vaultCipherText string) (string, int, error) {
 	regex := regexp.MustCompile(`^vault:v(\d+):(.+)$`)
@@ -476,13 +641,9 @@ func EncodeCipherText(cipherText string, keyVersion int) string {
 }
 
 func (t *Transit) mapError(err error) error {
-	resErr := &api.ResponseError{}
-	if errors.As(err, &resErr) {
-		if resErr.StatusCode == http.StatusBadRequest {
-			if len(resErr.Errors) == 1 && resErr.Errors[0] == "encryption key not found" {
-				return ErrEncKeyNotFound
-			}
-		}
+	vaultErr := &Error{}
+	if errors.As(err, &vaultErr) && vaultErr.StatusCode == http.StatusBadRequest && vaultErr.hasMessage("encryption key not found") {
+		return vaultErr.withErr(ErrEncKeyNotFound)
 	}
 
 	return err
@@ -0,0 +1,144 @@
+package vault
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TransitDecryptCache is a LRU cache for plaintexts returned by Transit.Decrypt and Transit.DecryptBatch.
+// It can be shared by multiple Transit services, entries are kept per namespace and mount point.
+// The internal copy of a plaintext is zeroed when it's evicted, expires or gets invalidated. Decrypt returns
+// plaintexts as strings, so the copies handed out for cache hits can't be zeroed and stay in memory until
+// they are garbage collected.
+//
+// Cache hits are answered without a request to vault, so vault's policy check is skipped for them: a token whose
+// access to a key was revoked can still decrypt cached ciphertexts until they expire. Only share a cache between
+// services using tokens with the same permissions and choose the ttl accordingly.
+type TransitDecryptCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[transitCacheKey]*list.Element
+	lru     *list.List
+}
+
+type transitCacheKey struct {
+	namespace  string
+	mountPoint string
+	key        string
+	ciphertext string
+	context    string
+}
+
+type transitCacheEntry struct {
+	cacheKey  transitCacheKey
+	plaintext []byte
+	version   int
+	expires   time.Time
+}
+
+// NewTransitDecryptCache creates a cache holding at most maxEntries plaintexts for at most ttl.
+// A maxEntries or ttl of 0 disables the respective limit.
+func NewTransitDecryptCache(maxEntries int, ttl time.Duration) *TransitDecryptCache {
+	return &TransitDecryptCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    make(map[transitCacheKey]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (c *TransitDecryptCache) get(k transitCacheKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[k]
+	if !ok {
+		return "", false
+	}
+
+	entry := elem.Value.(*transitCacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expires) {
+		c.removeElement(elem)
+		return "", false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return string(entry.plaintext), true
+}
+
+func (c *TransitDecryptCache) add(k transitCacheKey, plaintext string) {
+	// ciphertexts in an unknown format are cached with version 0 and are
+	// therefore invalidated by every min_decryption_version change
+	_, version, _ := DecodeCipherText(k.ciphertext)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[k]; ok {
+		c.removeElement(elem)
+	}
+
+	entry := &transitCacheEntry{
+		cacheKey:  k,
+		plaintext: []byte(plaintext),
+		version:   version,
+		expires:   c.now().Add(c.ttl),
+	}
+	c.entries[k] = c.lru.PushFront(entry)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// invalidate removes all entries of the given key with a version below minVersion.
+// A minVersion of 0 removes all entries of the key.
+func (c *TransitDecryptCache) invalidate(namespace string, mountPoint string, key string, minVersion int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, elem := range c.entries {
+		if k.namespace != namespace || k.mountPoint != mountPoint || k.key != key {
+			continue
+		}
+
+		if minVersion == 0 || elem.Value.(*transitCacheEntry).version < minVersion {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Purge removes all entries from the cache
+func (c *TransitDecryptCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, elem := range c.entries {
+		c.removeElement(elem)
+	}
+}
+
+// Len returns the number of cached plaintexts, including expired ones that were not evicted yet
+func (c *TransitDecryptCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *TransitDecryptCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*transitCacheEntry)
+
+	for i := range entry.plaintext {
+		entry.plaintext[i] = 0
+	}
+
+	c.lru.Remove(elem)
+	delete(c.entries, entry.cacheKey)
+}
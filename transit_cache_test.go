@@ -0,0 +1,225 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransitDecryptCacheTestSuite struct {
+	suite.Suite
+}
+
+func TestTransitDecryptCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(TransitDecryptCacheTestSuite))
+}
+
+func cacheKey(key string, ciphertext string) transitCacheKey {
+	return transitCacheKey{mountPoint: "transit", key: key, ciphertext: ciphertext}
+}
+
+func (s *TransitDecryptCacheTestSuite) TestGetAndAdd() {
+	c := NewTransitDecryptCache(0, 0)
+
+	_, ok := c.get(cacheKey("key", "vault:v1:abc"))
+	s.False(ok)
+
+	c.add(cacheKey("key", "vault:v1:abc"), "test")
+
+	plaintext, ok := c.get(cacheKey("key", "vault:v1:abc"))
+	s.True(ok)
+	s.Equal("test", plaintext)
+
+	_, ok = c.get(transitCacheKey{mountPoint: "other", key: "key", ciphertext: "vault:v1:abc"})
+	s.False(ok)
+}
+
+func (s *TransitDecryptCacheTestSuite) TestEvictLeastRecentlyUsed() {
+	c := NewTransitDecryptCache(2, 0)
+
+	c.add(cacheKey("key", "vault:v1:a"), "a")
+	c.add(cacheKey("key", "vault:v1:b"), "b")
+	_, _ = c.get(cacheKey("key", "vault:v1:a"))
+	c.add(cacheKey("key", "vault:v1:c"), "c")
+
+	s.Equal(2, c.Len())
+	_, ok := c.get(cacheKey("key", "vault:v1:b"))
+	s.False(ok)
+	_, ok = c.get(cacheKey("key", "vault:v1:a"))
+	s.True(ok)
+}
+
+func (s *TransitDecryptCacheTestSuite) TestTTL() {
+	now := time.Now()
+	c := NewTransitDecryptCache(0, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.add(cacheKey("key", "vault:v1:a"), "a")
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.get(cacheKey("key", "vault:v1:a"))
+	s.True(ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get(cacheKey("key", "vault:v1:a"))
+	s.False(ok)
+	s.Equal(0, c.Len())
+}
+
+func (s *TransitDecryptCacheTestSuite) TestZeroOnEviction() {
+	c := NewTransitDecryptCache(1, 0)
+
+	c.add(cacheKey("key", "vault:v1:a"), "secret")
+	plaintext := c.entries[cacheKey("key", "vault:v1:a")].Value.(*transitCacheEntry).plaintext
+
+	c.add(cacheKey("key", "vault:v1:b"), "b")
+
+	s.Equal(make([]byte, len("secret")), plaintext)
+}
+
+func (s *TransitDecryptCacheTestSuite) TestInvalidate() {
+	c := NewTransitDecryptCache(0, 0)
+
+	c.add(cacheKey("key", "vault:v1:a"), "a")
+	c.add(cacheKey("key", "vault:v2:b"), "b")
+	c.add(cacheKey("other", "vault:v1:c"), "c")
+
+	c.invalidate("", "transit", "key", 2)
+	_, ok := c.get(cacheKey("key", "vault:v1:a"))
+	s.False(ok)
+	_, ok = c.get(cacheKey("key", "vault:v2:b"))
+	s.True(ok)
+
+	c.invalidate("", "transit", "key", 0)
+	_, ok = c.get(cacheKey("key", "vault:v2:b"))
+	s.False(ok)
+	_, ok = c.get(cacheKey("other", "vault:v1:c"))
+	s.True(ok)
+}
+
+func TestTransitDecryptCacheInvalidatedByUpdate(t *testing.T) {
+	decrypts := 0
+	var config map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/transit/decrypt/key":
+			decrypts++
+			_, _ = w.Write([]byte(`{"data":{"plaintext":"dGVzdA=="}}`))
+		case "/v1/transit/keys/key/config":
+			if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	transit := client.Transit().WithDecryptCache(NewTransitDecryptCache(0, 0))
+
+	for _, ciphertext := range []string{"vault:v1:abc", "vault:v2:abc", "vault:v1:abc", "vault:v2:abc"} {
+		_, err = transit.Decrypt("key", &TransitDecryptOptions{Ciphertext: ciphertext})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, decrypts)
+
+	require.NoError(t, transit.Update("key", TransitUpdateOptions{MinDecryptionVersion: 2}))
+	require.Equal(t, float64(2), config["min_decryption_version"])
+
+	// only the entry below the new min_decryption_version was evicted
+	_, err = transit.Decrypt("key", &TransitDecryptOptions{Ciphertext: "vault:v2:abc"})
+	require.NoError(t, err)
+	require.Equal(t, 2, decrypts)
+
+	_, err = transit.Decrypt("key", &TransitDecryptOptions{Ciphertext: "vault:v1:abc"})
+	require.NoError(t, err)
+	require.Equal(t, 3, decrypts)
+}
+
+func TestTransitDecryptCacheBatchItemError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"batch_results": []map[string]string{
+					{"plaintext": base64.StdEncoding.EncodeToString([]byte("a"))},
+					{"error": "cipher: message authentication failed"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	cache := NewTransitDecryptCache(0, 0)
+	col, err := NewTransitColumn(client.Transit().WithDecryptCache(cache), "key")
+	require.NoError(t, err)
+
+	batch := col.Batch()
+	a, b := batch.NewValue(), batch.NewValue()
+	require.NoError(t, a.Scan("vault:v1:a"))
+	require.NoError(t, b.Scan("vault:v1:b"))
+
+	require.Error(t, batch.Decrypt())
+	require.Equal(t, "a", a.Plaintext)
+	require.Equal(t, "", b.Plaintext)
+
+	// failed items aren't cached
+	require.Equal(t, 1, cache.Len())
+	require.Equal(t, 1, requests)
+}
+
+func TestTransitDecryptCacheNamespaces(t *testing.T) {
+	decrypts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/transit/decrypt/key" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		decrypts++
+
+		plaintext := base64.StdEncoding.EncodeToString([]byte(r.Header.Get("X-Vault-Namespace")))
+		_, _ = w.Write([]byte(`{"data":{"plaintext":"` + plaintext + `"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	cache := NewTransitDecryptCache(0, 0)
+	tenantA := client.InNamespace("tenant-a").Transit().WithDecryptCache(cache)
+	tenantB := client.InNamespace("tenant-b").Transit().WithDecryptCache(cache)
+
+	for _, transit := range []*Transit{tenantA, tenantB, tenantA, tenantB} {
+		res, err := transit.Decrypt("key", &TransitDecryptOptions{Ciphertext: "vault:v1:abc"})
+		require.NoError(t, err)
+		require.Equal(t, transit.client.Namespace(), res.Data.Plaintext)
+	}
+
+	// each namespace has to be authorized by vault once
+	require.Equal(t, 2, decrypts)
+
+	// rotating the key of one namespace keeps the entries of the other one
+	require.NoError(t, tenantA.Rotate("key"))
+	_, err = tenantB.Decrypt("key", &TransitDecryptOptions{Ciphertext: "vault:v1:abc"})
+	require.NoError(t, err)
+	require.Equal(t, 2, decrypts)
+}
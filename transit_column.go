@@ -0,0 +1,217 @@
+package vault
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+func NewTransitColumn(t *Transit, key string, opts ...TransitColumnOpt) (*TransitColumn, error) {
+	c := &TransitColumn{
+		transit: t,
+		key:     key,
+	}
+
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// TransitColumn encrypts and decrypts database column values using a transit key.
+// Values are created with NewValue and can be used directly as query arguments and scan destinations.
+type TransitColumn struct {
+	transit *Transit
+	key     string
+	context string
+}
+
+type TransitColumnOpt func(c *TransitColumn) error
+
+// WithColumnContext sets the key derivation context used for every value of the column.
+// In combination with a key created with Derived and ConvergentEncryption set, equal plaintexts
+// result in equal ciphertexts, which allows searching the column by ciphertext.
+func WithColumnContext(context string) TransitColumnOpt {
+	return func(c *TransitColumn) error {
+		if context == "" {
+			return errors.New("column context must not be empty")
+		}
+
+		c.context = base64.StdEncoding.EncodeToString([]byte(context))
+
+		return nil
+	}
+}
+
+// NewValue returns a valid (non NULL) value holding the given plaintext
+func (c *TransitColumn) NewValue(plaintext string) *TransitColumnValue {
+	return &TransitColumnValue{
+		column:    c,
+		Plaintext: plaintext,
+		Valid:     true,
+	}
+}
+
+// Encrypt returns the ciphertext for plaintext as it would be stored in the column.
+// It can be used to build WHERE clauses for columns using convergent encryption.
+func (c *TransitColumn) Encrypt(plaintext string) (string, error) {
+	res, err := c.transit.Encrypt(c.key, &TransitEncryptOptions{
+		Plaintext: plaintext,
+		Context:   c.context,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.Data.Ciphertext, nil
+}
+
+// Decrypt returns the plaintext of a ciphertext read from the column
+func (c *TransitColumn) Decrypt(ciphertext string) (string, error) {
+	res, err := c.transit.Decrypt(c.key, &TransitDecryptOptions{
+		Ciphertext: ciphertext,
+		Context:    c.context,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.Data.Plaintext, nil
+}
+
+// Batch returns a TransitColumnBatch for scanning a result set with a single decryption request
+func (c *TransitColumn) Batch() *TransitColumnBatch {
+	return &TransitColumnBatch{
+		column: c,
+	}
+}
+
+// errNoColumn is returned by values not created by TransitColumn.NewValue or TransitColumnBatch.NewValue
+var errNoColumn = errors.New("transit column value has no column, create it with TransitColumn.NewValue")
+
+// TransitColumnValue implements driver.Valuer and sql.Scanner. The plaintext is encrypted when the value
+// is written to the database and decrypted when it is scanned. Values have to be created by
+// TransitColumn.NewValue or TransitColumnBatch.NewValue, the zero value doesn't know the key to use.
+type TransitColumnValue struct {
+	column *TransitColumn
+	batch  *TransitColumnBatch
+
+	Plaintext string
+	// Valid is false if the column is NULL
+	Valid bool
+}
+
+func (v TransitColumnValue) Value() (driver.Value, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+
+	if v.column == nil {
+		return nil, errNoColumn
+	}
+
+	return v.column.Encrypt(v.Plaintext)
+}
+
+func (v *TransitColumnValue) Scan(src interface{}) error {
+	if v.column == nil {
+		return errNoColumn
+	}
+
+	var ciphertext string
+
+	switch s := src.(type) {
+	case nil:
+		v.Plaintext, v.Valid = "", false
+		return nil
+	case string:
+		ciphertext = s
+	case []byte:
+		ciphertext = string(s)
+	default:
+		return fmt.Errorf("unsupported type %T for transit column value", src)
+	}
+
+	v.Valid = true
+
+	if v.batch != nil {
+		v.batch.pending = append(v.batch.pending, pendingColumnValue{value: v, ciphertext: ciphertext})
+		return nil
+	}
+
+	plaintext, err := v.column.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+
+	v.Plaintext = plaintext
+
+	return nil
+}
+
+// TransitColumnBatch collects the ciphertexts of scanned values and decrypts all of them with
+// a single DecryptBatch request. Values returned by NewValue are only usable after Decrypt was called.
+type TransitColumnBatch struct {
+	column  *TransitColumn
+	pending []pendingColumnValue
+}
+
+type pendingColumnValue struct {
+	value      *TransitColumnValue
+	ciphertext string
+}
+
+// NewValue returns a scan destination whose decryption is deferred until Decrypt is called
+func (b *TransitColumnBatch) NewValue() *TransitColumnValue {
+	return &TransitColumnValue{
+		column: b.column,
+		batch:  b,
+	}
+}
+
+// Decrypt decrypts all values scanned since the last call. The plaintexts of all values which could be decrypted
+// are set even if it returns an error for a failed item.
+func (b *TransitColumnBatch) Decrypt() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	input := make([]TransitBatchCiphertext, len(b.pending))
+	for i, p := range b.pending {
+		input[i] = TransitBatchCiphertext{
+			Ciphertext: p.ciphertext,
+			Context:    b.column.context,
+		}
+	}
+
+	res, err := b.column.transit.DecryptBatch(b.column.key, TransitDecryptOptionsBatch{
+		BatchInput: input,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(res.Data.BatchResults) != len(b.pending) {
+		return fmt.Errorf("expected %d batch results, got %d", len(b.pending), len(res.Data.BatchResults))
+	}
+
+	var itemErr error
+	for i, p := range b.pending {
+		result := res.Data.BatchResults[i]
+		p.value.Plaintext = result.Plaintext
+
+		if result.Error != "" && itemErr == nil {
+			itemErr = fmt.Errorf("decrypting batch item %d failed: %s", i, result.Error)
+		}
+	}
+
+	b.pending = nil
+
+	return itemErr
+}
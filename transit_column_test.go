@@ -0,0 +1,54 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitColumnValueWithoutColumn(t *testing.T) {
+	v := TransitColumnValue{Plaintext: "test", Valid: true}
+
+	_, err := v.Value()
+	require.Error(t, err)
+	require.Error(t, v.Scan("vault:v1:abc"))
+
+	// NULL values don't need a column
+	v.Valid = false
+	value, err := v.Value()
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestTransitColumnBatchItemError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"batch_results": []map[string]string{
+					{"plaintext": base64.StdEncoding.EncodeToString([]byte("a"))},
+					{"error": "cipher: message authentication failed"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, WithAuthToken("token"))
+	require.NoError(t, err)
+
+	col, err := NewTransitColumn(client.Transit(), "key")
+	require.NoError(t, err)
+
+	batch := col.Batch()
+	a, b := batch.NewValue(), batch.NewValue()
+	require.NoError(t, a.Scan("vault:v1:a"))
+	require.NoError(t, b.Scan("vault:v1:b"))
+
+	require.Error(t, batch.Decrypt())
+	require.Equal(t, "a", a.Plaintext)
+	require.Equal(t, "", b.Plaintext)
+}
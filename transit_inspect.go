@@ -0,0 +1,90 @@
+package vault
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var transitValueRegex = regexp.MustCompile(`^vault:v(\d+):(.+)$`)
+
+// TransitValue is a parsed ciphertext, signature or HMAC in the "vault:v<ver>:<base64>" format
+type TransitValue struct {
+	KeyVersion int
+	Payload    []byte
+}
+
+// ParseTransitCipherText parses a ciphertext returned by Transit.Encrypt without contacting vault
+func ParseTransitCipherText(value string) (*TransitValue, error) {
+	return parseTransitValue("ciphertext", value, base64.StdEncoding)
+}
+
+// ParseTransitHMAC parses a HMAC returned by the transit hmac endpoint without contacting vault
+func ParseTransitHMAC(value string) (*TransitValue, error) {
+	return parseTransitValue("hmac", value, base64.StdEncoding)
+}
+
+// ParseTransitSignature parses a signature returned by Transit.Sign without contacting vault.
+// Signatures using the "jws" marshaling algorithm are encoded using unpadded base64url and are detected automatically.
+func ParseTransitSignature(value string) (*TransitValue, error) {
+	return parseTransitValue("signature", value, base64.StdEncoding, base64.RawURLEncoding)
+}
+
+func parseTransitValue(kind string, value string, encodings ...*base64.Encoding) (*TransitValue, error) {
+	matches := transitValueRegex.FindStringSubmatch(value)
+	if len(matches) != 3 {
+		return nil, fmt.Errorf("invalid vault %s format", kind)
+	}
+
+	keyVersion, err := strconv.Atoi(matches[1])
+	if err != nil || keyVersion < 1 {
+		return nil, fmt.Errorf("invalid key version %q in vault %s", matches[1], kind)
+	}
+
+	var payload []byte
+	for _, encoding := range encodings {
+		payload, err = encoding.DecodeString(matches[2])
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 payload in vault %s: %w", kind, err)
+	}
+
+	return &TransitValue{
+		KeyVersion: keyVersion,
+		Payload:    payload,
+	}, nil
+}
+
+// TransitVersionViolation describes a ciphertext that can't be decrypted with a given min_decryption_version
+type TransitVersionViolation struct {
+	// Index of the ciphertext in the checked slice
+	Index      int
+	KeyVersion int
+	// Err is set if the ciphertext could not be parsed
+	Err error
+}
+
+// CheckMinDecryptionVersion returns all ciphertexts that are invalid or whose key version is below minVersion.
+// Use the MinDecryptionVersion of TransitReadResponseData to find data that can't be decrypted anymore,
+// or the version you plan to configure to find data that has to be rewrapped before trimming old versions.
+func CheckMinDecryptionVersion(minVersion int, ciphertexts []string) []TransitVersionViolation {
+	var violations []TransitVersionViolation
+
+	for i, c := range ciphertexts {
+		parsed, err := ParseTransitCipherText(c)
+		if err != nil {
+			violations = append(violations, TransitVersionViolation{Index: i, Err: err})
+			continue
+		}
+
+		if parsed.KeyVersion < minVersion {
+			violations = append(violations, TransitVersionViolation{Index: i, KeyVersion: parsed.KeyVersion})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TransitInspectTestSuite struct {
+	suite.Suite
+}
+
+func TestTransitInspectTestSuite(t *testing.T) {
+	suite.Run(t, new(TransitInspectTestSuite))
+}
+
+func (s *TransitInspectTestSuite) TestParseCipherText() {
+	v, err := ParseTransitCipherText("vault:v3:dGVzdA==")
+	s.NoError(err)
+	s.Equal(3, v.KeyVersion)
+	s.Equal([]byte("test"), v.Payload)
+}
+
+func (s *TransitInspectTestSuite) TestParseCipherTextErrors() {
+	_, err := ParseTransitCipherText("vault:dGVzdA==")
+	s.Error(err)
+
+	_, err = ParseTransitCipherText("vault:v0:dGVzdA==")
+	s.Error(err)
+
+	_, err = ParseTransitCipherText("vault:v1:not base64!")
+	s.Error(err)
+}
+
+func (s *TransitInspectTestSuite) TestParseSignature() {
+	v, err := ParseTransitSignature("vault:v1:dGVzdA==")
+	s.NoError(err)
+	s.Equal([]byte("test"), v.Payload)
+
+	// jws marshaling
+	v, err = ParseTransitSignature("vault:v2:-_8")
+	s.NoError(err)
+	s.Equal(2, v.KeyVersion)
+	s.Equal([]byte{0xfb, 0xff}, v.Payload)
+}
+
+func (s *TransitInspectTestSuite) TestParseHMAC() {
+	v, err := ParseTransitHMAC("vault:v1:dGVzdA==")
+	s.NoError(err)
+	s.Equal(1, v.KeyVersion)
+}
+
+func (s *TransitInspectTestSuite) TestCheckMinDecryptionVersion() {
+	violations := CheckMinDecryptionVersion(2, []string{
+		"vault:v1:dGVzdA==",
+		"vault:v2:dGVzdA==",
+		"invalid",
+		"vault:v3:dGVzdA==",
+	})
+
+	s.Len(violations, 2)
+	s.Equal(0, violations[0].Index)
+	s.Equal(1, violations[0].KeyVersion)
+	s.NoError(violations[0].Err)
+	s.Equal(2, violations[1].Index)
+	s.Error(violations[1].Err)
+}
@@ -2,6 +2,7 @@ package vault
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"testing"
 	"time"
@@ -213,7 +214,25 @@ func (s *TransitTestSuite) TestDecryptWithoutKey() {
 	_, err := s.client.Decrypt("test404", &TransitDecryptOptions{
 		Ciphertext: "asdf",
 	})
-	s.Equal(ErrEncKeyNotFound, err)
+	s.ErrorIs(err, ErrEncKeyNotFound)
+}
+
+func (s *TransitTestSuite) TestReadNotFound() {
+	_, err := s.client.Read("read-not-found")
+	s.ErrorIs(err, ErrNotFound)
+
+	_, err = s.client.Export("read-not-found", TransitExportOptions{KeyType: "encryption-key"})
+	s.ErrorIs(err, ErrNotFound)
+
+	res, err := s.client.ReadOrNil("read-not-found")
+	require.NoError(s.T(), err)
+	s.Nil(res)
+
+	require.NoError(s.T(), s.client.Create("read-not-found", &TransitCreateOptions{}))
+
+	res, err = s.client.ReadOrNil("read-not-found")
+	require.NoError(s.T(), err)
+	s.Equal("read-not-found", res.Data.Name)
 }
 
 func (s *TransitTestSuite) TestDecryptWithBadCipher() {
@@ -286,6 +305,72 @@ func (s *TransitTestSuite) TestSignVerifyBatch() {
 	s.True(verifyRes.Data.BatchResults[1].Valid)
 }
 
+func (s *TransitTestSuite) TestRandom() {
+	b, err := s.client.Random(TransitRandomOptions{Bytes: 16})
+	require.NoError(s.T(), err)
+	s.Len(b, 16)
+
+	b, err = s.client.Random(TransitRandomOptions{Bytes: 8, Format: "hex"})
+	require.NoError(s.T(), err)
+	s.Len(b, 8)
+}
+
+func (s *TransitTestSuite) TestHash() {
+	sum, err := s.client.Hash(TransitHashOptions{Input: "test"})
+	require.NoError(s.T(), err)
+
+	expected := sha256.Sum256([]byte("test"))
+	s.Equal(expected[:], sum)
+}
+
+func (s *TransitTestSuite) TestColumnValueScan() {
+	err := s.client.Create("testColumnValueScan", &TransitCreateOptions{})
+	require.NoError(s.T(), err)
+
+	col, err := NewTransitColumn(s.client, "testColumnValueScan")
+	require.NoError(s.T(), err)
+
+	enc, err := col.NewValue("test").Value()
+	require.NoError(s.T(), err)
+
+	dec := col.NewValue("")
+	require.NoError(s.T(), dec.Scan([]byte(enc.(string))))
+	s.True(dec.Valid)
+	s.Equal("test", dec.Plaintext)
+
+	require.NoError(s.T(), dec.Scan(nil))
+	s.False(dec.Valid)
+}
+
+func (s *TransitTestSuite) TestColumnConvergentBatch() {
+	err := s.client.Create("testColumnConvergentBatch", &TransitCreateOptions{
+		Derived:              BoolPtr(true),
+		ConvergentEncryption: BoolPtr(true),
+	})
+	require.NoError(s.T(), err)
+
+	col, err := NewTransitColumn(s.client, "testColumnConvergentBatch", WithColumnContext("users.email"))
+	require.NoError(s.T(), err)
+
+	enc1, err := col.Encrypt("test1")
+	require.NoError(s.T(), err)
+	enc1Again, err := col.Encrypt("test1")
+	require.NoError(s.T(), err)
+	s.Equal(enc1, enc1Again)
+
+	enc2, err := col.Encrypt("test2")
+	require.NoError(s.T(), err)
+
+	batch := col.Batch()
+	v1, v2 := batch.NewValue(), batch.NewValue()
+	require.NoError(s.T(), v1.Scan(enc1))
+	require.NoError(s.T(), v2.Scan(enc2))
+	require.NoError(s.T(), batch.Decrypt())
+
+	s.Equal("test1", v1.Plaintext)
+	s.Equal("test2", v2.Plaintext)
+}
+
 func (s *TransitTestSuite) TestDecodeCipherText() {
 	dec, ver, err := DecodeCipherText("vault:v123:SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c")
 	require.NoError(s.T(), err)
@@ -1,6 +1,14 @@
 package vault
 
-import "strings"
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 func resolvePath(parts []string) string {
 	trimmedParts := make([]string, len(parts))
@@ -12,6 +20,57 @@ func resolvePath(parts []string) string {
 	return "/" + strings.Join(trimmedParts, "/")
 }
 
+// decodeFormatted decodes values returned by endpoints supporting the "format" parameter ("base64" or "hex")
+func decodeFormatted(format string, value string) ([]byte, error) {
+	switch format {
+	case "", "base64":
+		return base64.StdEncoding.DecodeString(value)
+	case "hex":
+		return hex.DecodeString(value)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// writeFileAtomic replaces the file at path with data using a temporary file and a rename.
+// It returns false without touching the file if it already contains data.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (bool, error) {
+	current, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(current, data) {
+		return false, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func BoolPtr(input bool) *bool {
 	b := input
 	return &b
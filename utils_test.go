@@ -1,8 +1,11 @@
 package vault
 
 import (
-	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/suite"
 )
 
 type UtilsTestSuite struct {
@@ -23,3 +26,44 @@ func (s *UtilsTestSuite) TestResolvePathMultipleParts() {
 func (s *UtilsTestSuite) TestResolvePathMultipleSlashes() {
 	s.Equal("/test/foo/bla/bar", resolvePath([]string{"/test", "/////foo/bla/", "/bar///"}))
 }
+
+func (s *UtilsTestSuite) TestDecodeFormatted() {
+	b, err := decodeFormatted("", "dGVzdA==")
+	s.NoError(err)
+	s.Equal([]byte("test"), b)
+
+	b, err = decodeFormatted("hex", "74657374")
+	s.NoError(err)
+	s.Equal([]byte("test"), b)
+
+	_, err = decodeFormatted("foo", "test")
+	s.Error(err)
+}
+
+func (s *UtilsTestSuite) TestWriteFileAtomic() {
+	path := filepath.Join(s.T().TempDir(), "test")
+
+	changed, err := writeFileAtomic(path, []byte("test"), 0o600)
+	s.NoError(err)
+	s.True(changed)
+
+	changed, err = writeFileAtomic(path, []byte("test"), 0o600)
+	s.NoError(err)
+	s.False(changed)
+
+	changed, err = writeFileAtomic(path, []byte("test2"), 0o600)
+	s.NoError(err)
+	s.True(changed)
+
+	content, err := os.ReadFile(path)
+	s.NoError(err)
+	s.Equal([]byte("test2"), content)
+
+	info, err := os.Stat(path)
+	s.NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	s.NoError(err)
+	s.Len(entries, 1)
+}